@@ -2,13 +2,64 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"Coding/games/models"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	// Headless mode: speak the Universal Hive Protocol for GUIs and engine tournaments
+	if len(os.Args) > 1 && os.Args[1] == "uhp" {
+		if err := models.RunUHP(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running UHP engine: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	
+	// Headless mode: pit the alpha-beta engine against MCTS, e.g. "match 10" for ten games
+	if len(os.Args) > 1 && os.Args[1] == "match" {
+		games := 2
+		if len(os.Args) > 2 {
+			n, err := strconv.Atoi(os.Args[2])
+			if err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "Invalid number of games: %s\n", os.Args[2])
+				os.Exit(1)
+			}
+			games = n
+		}
+		models.RunMatch(os.Stdout, models.NewAlphaBetaEngine(), models.NewMCTSEngine(), games, models.BaseRules, models.SearchLimits{Time: time.Second})
+		return
+	}
+	
+	// Headless mode: measure how the search speeds up with more workers, e.g. "bench 8"
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		maxWorkers := runtime.NumCPU()
+		if len(os.Args) > 2 {
+			n, err := strconv.Atoi(os.Args[2])
+			if err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "Invalid number of workers: %s\n", os.Args[2])
+				os.Exit(1)
+			}
+			maxWorkers = n
+		}
+		counts := []int{}
+		for n := 1; n < maxWorkers; n *= 2 {
+			counts = append(counts, n)
+		}
+		counts = append(counts, maxWorkers)
+		if _, err := models.MeasureSearchSpeed(os.Stdout, counts, 3*time.Second); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running benchmark: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	
 	// Phase 1: Game Selection
 	menuModel := models.NewMenuModel()
 	p := tea.NewProgram(menuModel)
@@ -39,8 +90,24 @@ func main() {
 
 	// Phase 2: Game Interface - Use special HiveModel for Hive game
 	if selectedGame.Name == "Hive" {
+		// Choose the expansions before the game starts
+		p = tea.NewProgram(models.NewHiveSetupModel())
+		finalSetup, err := p.Run()
+		if err != nil {
+			fmt.Printf("Error running Hive setup: %v\n", err)
+			return
+		}
+		setup := finalSetup.(models.HiveSetupModel)
+		if !setup.Started() {
+			fmt.Println("\nHive setup cancelled. Goodbye!")
+			return
+		}
+		
 		// Use 4-panel Hive interface
-		hiveModel := models.NewHiveModel(selectedGame)
+		hiveModel := models.NewHiveModel(selectedGame, setup.Rules())
+		if setup.VersusComputer() {
+			hiveModel = hiveModel.WithComputer(setup.Difficulty(), models.Black)
+		}
 		p = tea.NewProgram(hiveModel, tea.WithAltScreen())
 		if _, err := p.Run(); err != nil {
 			fmt.Printf("Error running Hive interface: %v\n", err)
@@ -58,6 +125,6 @@ func main() {
 	}
 
 	// Exit message
-	fmt.Println("\nThanks for playing! See you next time!\n")
+	fmt.Println("\nThanks for playing! See you next time!")
 }
 
@@ -0,0 +1,406 @@
+package models
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSearchDepth caps iterative deepening when only a time budget is given
+const maxSearchDepth = 64
+
+// ttBound says how a transposition table score relates to the true value of a position
+type ttBound int
+
+const (
+	exactBound ttBound = iota
+	lowerBound         // The search failed high: the position is worth at least the score
+	upperBound         // The search failed low: the position is worth at most the score
+)
+
+// ttEntry remembers what a search found out about a position
+type ttEntry struct {
+	depth int
+	score int // Forced wins and losses count their plies from this position, not from the root
+	bound ttBound
+	move  Move
+}
+
+// ttShards is how many independently locked parts the transposition table is split into,
+// so that parallel workers rarely wait for each other
+const ttShards = 64
+
+// transpositionTable is shared by the workers of a search; each shard has its own lock
+type transpositionTable struct {
+	shards [ttShards]ttShard
+}
+
+type ttShard struct {
+	mu      sync.Mutex
+	entries map[uint64]ttEntry
+}
+
+func newTranspositionTable() *transpositionTable {
+	t := &transpositionTable{}
+	for i := range t.shards {
+		t.shards[i].entries = make(map[uint64]ttEntry)
+	}
+	return t
+}
+
+// get looks up a position by Zobrist key
+func (t *transpositionTable) get(key uint64) (ttEntry, bool) {
+	shard := &t.shards[key%ttShards]
+	shard.mu.Lock()
+	entry, found := shard.entries[key]
+	shard.mu.Unlock()
+	return entry, found
+}
+
+// put records what a search found out about a position, replacing what was known before
+func (t *transpositionTable) put(key uint64, entry ttEntry) {
+	shard := &t.shards[key%ttShards]
+	shard.mu.Lock()
+	shard.entries[key] = entry
+	shard.mu.Unlock()
+}
+
+// AlphaBetaEngine searches with negamax and alpha-beta pruning, deepening one ply at a
+// time so that the best move of each iteration is tried first in the next one
+// With several workers it runs a Lazy SMP search: every worker searches the whole tree on
+// its own copy of the game, and what one finds reaches the others through the shared
+// transposition table; the first worker's move is played
+type AlphaBetaEngine struct {
+	Weights EvalWeights // Scores the positions where the search stops
+	Noise   int         // Largest error added to each evaluation, to play weaker on purpose
+	Blunder float64     // Chance of playing a random move instead of searching, 0 to 1
+	Workers int         // Goroutines searching together, 1 or less for a single-threaded search
+
+	mu     sync.Mutex // Guards rng and search, as a search may be started while another runs
+	rng    *rand.Rand
+	search *abSearch // The latest search, stopped when a new one starts
+}
+
+// abSearch is everything one search works with, so that a search left running after it
+// was stopped never shares a table, clock or flag with the next one
+type abSearch struct {
+	weights   EvalWeights
+	noise     int
+	noiseSeed uint64 // Drawn for each search, so a position keeps the same error throughout it
+	table     *transpositionTable
+	deadline  time.Time
+	stopped   atomic.Bool  // Set when the time runs out, the first worker is done or Stop is called
+	nodes     atomic.Int64 // Positions visited by all workers
+}
+
+// abWorker is one goroutine of a search, with its own copy of the game
+type abWorker struct {
+	search *abSearch
+	id     int
+	state  *GameState
+	moves  []Move // Root moves, in this worker's order
+	nodes  int64
+}
+
+// NewAlphaBetaEngine creates an alpha-beta engine with the default evaluation weights,
+// searching with one worker per CPU
+func NewAlphaBetaEngine() *AlphaBetaEngine {
+	return &AlphaBetaEngine{
+		Weights: DefaultEvalWeights,
+		Workers: runtime.NumCPU(),
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Name identifies the engine in menus and match results
+func (e *AlphaBetaEngine) Name() string {
+	return "Alpha-beta"
+}
+
+// Nodes returns how many positions the last search visited, over all its workers
+func (e *AlphaBetaEngine) Nodes() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.search == nil {
+		return 0
+	}
+	return e.search.nodes.Load()
+}
+
+// Stop ends the running search, if any; its BestMove returns the best move found so far
+func (e *AlphaBetaEngine) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.search != nil {
+		e.search.stopped.Store(true)
+	}
+}
+
+// BestMove searches a copy of the game within the limits and returns the best move found
+func (e *AlphaBetaEngine) BestMove(g *GameState, limits SearchLimits) Move {
+	state := g.Clone()
+	moves := state.LegalMoves()
+	if len(moves) == 0 {
+		return Move{Type: PassMove}
+	}
+	if len(moves) == 1 {
+		return moves[0]
+	}
+
+	maxDepth := limits.Depth
+	if maxDepth <= 0 {
+		maxDepth = maxSearchDepth
+		if limits.Time <= 0 {
+			maxDepth = 3
+		}
+	}
+	search, blunder := e.startSearch(limits)
+	if blunder {
+		return moves[e.randomIndex(len(moves))]
+	}
+
+	// Helpers search until the first worker has its answer
+	var helpers sync.WaitGroup
+	for id := 1; id < e.Workers; id++ {
+		helpers.Add(1)
+		go func(w *abWorker) {
+			defer helpers.Done()
+			w.deepen(maxDepth)
+		}(search.newWorker(id, state.Clone(), moves))
+	}
+
+	best := search.newWorker(0, state, moves).deepen(maxDepth)
+	search.stopped.Store(true)
+	helpers.Wait()
+	return best
+}
+
+// startSearch sets up a search with the engine's current settings and stops the one before,
+// whose BestMove may still be running; it also says whether to blunder instead of searching
+func (e *AlphaBetaEngine) startSearch(limits SearchLimits) (*abSearch, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	search := &abSearch{
+		weights:   e.Weights,
+		noise:     e.Noise,
+		noiseSeed: e.rng.Uint64(),
+		table:     newTranspositionTable(),
+	}
+	if limits.Time > 0 {
+		search.deadline = time.Now().Add(limits.Time)
+	}
+	if e.search != nil {
+		e.search.stopped.Store(true)
+	}
+	e.search = search
+	return search, e.Blunder > 0 && e.rng.Float64() < e.Blunder
+}
+
+// randomIndex draws a random index below n from the engine's generator
+func (e *AlphaBetaEngine) randomIndex(n int) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rng.Intn(n)
+}
+
+// newWorker sets up a worker on its own copy of the game and of the root moves
+// Helpers take the root moves in a different order, so they do not all start on the same one
+func (s *abSearch) newWorker(id int, state *GameState, moves []Move) *abWorker {
+	w := &abWorker{search: s, id: id, state: state, moves: append([]Move{}, moves...)}
+	if id > 0 {
+		shift := id % len(w.moves)
+		w.moves = append(w.moves[shift:], w.moves[:shift]...)
+	}
+	return w
+}
+
+// deepen runs iterative deepening up to maxDepth or until the search is stopped
+// Odd helpers start a ply deeper, so the workers spread over more than one depth at a time
+func (w *abWorker) deepen(maxDepth int) Move {
+	defer func() { w.search.nodes.Add(w.nodes) }()
+
+	best := w.moves[0]
+	for depth := 1 + w.id%2; depth <= maxDepth; depth++ {
+		move, score, ok := w.searchRoot(depth)
+		if !ok {
+			break
+		}
+		best = move
+		// A forced win or loss will not change with more depth
+		if score > winScoreBound || score < -winScoreBound {
+			break
+		}
+	}
+	return best
+}
+
+// searchRoot runs one iteration over the root moves, the previous best first
+// Each move is searched with the window opened by one below the best score, so a move that
+// ties with it gets its exact score; ties go to the move that comes first in notation, so
+// the choice depends neither on the order moves were generated in nor on the helpers
+// Returns false when the search was stopped before the iteration finished
+func (w *abWorker) searchRoot(depth int) (Move, int, bool) {
+	s, g := w.search, w.state
+	entry, _ := s.table.get(g.ZobristKey())
+	orderMoves(g, w.moves, entry.move)
+
+	alpha, beta := -WinScore-1, WinScore+1
+	best := w.moves[0]
+	for _, move := range w.moves {
+		g.apply(move)
+		score := -w.negamax(depth-1, 1, -beta, -(alpha - 1))
+		g.undo()
+		if s.stopped.Load() {
+			return best, alpha, false
+		}
+		if score > alpha || (score == alpha && move.String() < best.String()) {
+			alpha, best = score, move
+		}
+	}
+
+	s.table.put(g.ZobristKey(), ttEntry{depth: depth, score: alpha, bound: exactBound, move: best})
+	return best, alpha, true
+}
+
+// negamax returns the score of the position for the side to move, searched depth plies deep
+func (w *abWorker) negamax(depth, ply, alpha, beta int) int {
+	s, g := w.search, w.state
+	if w.timeUp() {
+		return 0
+	}
+	if g.IsOver() {
+		return terminalScore(g, ply)
+	}
+	if depth == 0 {
+		return s.evaluate(g)
+	}
+
+	key := g.ZobristKey()
+	entry, found := s.table.get(key)
+	if found && entry.depth >= depth {
+		score := scoreFromTable(entry.score, ply)
+		switch {
+		case entry.bound == exactBound:
+			return score
+		case entry.bound == lowerBound && score >= beta:
+			return score
+		case entry.bound == upperBound && score <= alpha:
+			return score
+		}
+	}
+
+	moves := g.LegalMoves()
+	orderMoves(g, moves, entry.move)
+
+	original := alpha
+	best := moves[0]
+	for _, move := range moves {
+		g.apply(move)
+		score := -w.negamax(depth-1, ply+1, -beta, -alpha)
+		g.undo()
+		if s.stopped.Load() {
+			return 0
+		}
+		if score > alpha {
+			alpha, best = score, move
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	bound := exactBound
+	if alpha <= original {
+		bound = upperBound
+	} else if alpha >= beta {
+		bound = lowerBound
+	}
+	s.table.put(key, ttEntry{depth: depth, score: scoreToTable(alpha, ply), bound: bound, move: best})
+	return alpha
+}
+
+// scoreToTable turns a forced win or loss counted from the root into one counted from the
+// position at ply, so the entry holds wherever the position is reached again
+func scoreToTable(score, ply int) int {
+	switch {
+	case score > winScoreBound:
+		return score + ply
+	case score < -winScoreBound:
+		return score - ply
+	}
+	return score
+}
+
+// scoreFromTable turns a forced win or loss stored by scoreToTable back into one counted
+// from the root of the search reaching the position at ply
+func scoreFromTable(score, ply int) int {
+	switch {
+	case score > winScoreBound:
+		return score - ply
+	case score < -winScoreBound:
+		return score + ply
+	}
+	return score
+}
+
+// evaluate scores a position where the search stops, with the engine's deliberate error
+func (s *abSearch) evaluate(g *GameState) int {
+	score := s.weights.Evaluate(g)
+	if s.noise > 0 {
+		score += int(mix64(g.ZobristKey()^s.noiseSeed)%uint64(2*s.noise+1)) - s.noise
+	}
+	return score
+}
+
+// terminalScore scores a finished game, preferring quicker wins and slower losses
+func terminalScore(g *GameState, ply int) int {
+	switch g.Result {
+	case Draw:
+		return 0
+	case winFor(g.ToMove):
+		return WinScore - ply
+	}
+	return -WinScore + ply
+}
+
+// timeUp checks the clock every so many nodes and stops every worker once it runs out
+func (w *abWorker) timeUp() bool {
+	s := w.search
+	w.nodes++
+	if w.nodes%256 == 0 && !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		s.stopped.Store(true)
+	}
+	return s.stopped.Load()
+}
+
+// orderMoves sorts the moves most promising first: the remembered best move, then moves that
+// close in on the opponent's Queen, then moves that free up the player's own
+func orderMoves(g *GameState, moves []Move, hint Move) {
+	opponentQueen, opponentFound := g.Board.FindPiece(NewPiece(QueenBee, g.ToMove.Opponent(), 0))
+	ownQueen, ownFound := g.Board.FindPiece(NewPiece(QueenBee, g.ToMove, 0))
+
+	priority := func(move Move) int {
+		if move == hint {
+			return 1000
+		}
+		p := pieceValues[move.Piece.Type] / 10
+		if opponentFound && move.To.Distance(opponentQueen) == 1 {
+			p += 100
+		}
+		if ownFound && move.Type == MovementMove && move.From.Distance(ownQueen) == 1 && move.To.Distance(ownQueen) > 1 {
+			p += 50
+		}
+		if move.Type == MovementMove {
+			p += 10
+		}
+		return p
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		return priority(moves[i]) > priority(moves[j])
+	})
+}
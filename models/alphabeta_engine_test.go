@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+// searchPositions are the positions the engine tests search
+var searchPositions = []string{
+	"Base - w 1 - Q1A3G3S2B2/Q1A3G3S2B2 -",
+	BenchmarkPosition,
+	"Base+MLP - w 5 0,0:wQ;1,0:bQ;-1,0:wP;2,0:bM;-1,1:wL;2,-1:bA1 Q0A3G3S2B2M1L0P0/Q0A2G3S2B2M0L1P1 bA1",
+}
+
+func parseSearchPosition(t testing.TB, s string) *GameState {
+	t.Helper()
+	g, err := ParsePosition(s)
+	if err != nil {
+		t.Fatalf("ParsePosition(%q): %v", s, err)
+	}
+	return g
+}
+
+func isLegal(g *GameState, move Move) bool {
+	for _, legal := range g.LegalMoves() {
+		if legal == move {
+			return true
+		}
+	}
+	return false
+}
+
+// TestParallelBestMoveIsLegal runs several workers at once; run it with -race
+func TestParallelBestMoveIsLegal(t *testing.T) {
+	for _, position := range searchPositions {
+		g := parseSearchPosition(t, position)
+		engine := NewAlphaBetaEngine()
+		engine.Workers = 4
+
+		move := engine.BestMove(g, SearchLimits{Depth: 3})
+		if !isLegal(g, move) {
+			t.Errorf("%s: BestMove returned illegal move %s", position, g.MoveString(move))
+		}
+		if engine.Nodes() == 0 {
+			t.Errorf("%s: search visited no nodes", position)
+		}
+	}
+}
+
+// TestFixedDepthSearchIsRepeatable searches each position several times with one worker
+// Legal moves come out in a different order every time, so this checks that tied moves are
+// settled by notation rather than by which was generated first
+func TestFixedDepthSearchIsRepeatable(t *testing.T) {
+	for _, position := range searchPositions {
+		g := parseSearchPosition(t, position)
+		for depth := 1; depth <= 3; depth++ {
+			var first Move
+			for run := 0; run < 3; run++ {
+				engine := NewAlphaBetaEngine()
+				engine.Workers = 1
+				move := engine.BestMove(g, SearchLimits{Depth: depth})
+				if run == 0 {
+					first = move
+				} else if move != first {
+					t.Errorf("%s depth %d: searches play %s and %s", position, depth, g.MoveString(first), g.MoveString(move))
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	g := parseSearchPosition(b, BenchmarkPosition)
+	for _, workers := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			var nodes int64
+			for i := 0; i < b.N; i++ {
+				engine := NewAlphaBetaEngine()
+				engine.Workers = workers
+				engine.BestMove(g, SearchLimits{Depth: 3})
+				nodes += engine.Nodes()
+			}
+			b.ReportMetric(float64(nodes)/b.Elapsed().Seconds(), "nodes/s")
+		})
+	}
+}
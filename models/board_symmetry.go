@@ -0,0 +1,98 @@
+package models
+
+// SymmetryCount is the number of ways the hex grid maps onto itself about a cell:
+// six rotations by 60°, each with or without a reflection
+const SymmetryCount = 12
+
+// symmetric applies symmetry n of the grid to a coordinate, about the origin
+// Symmetries 0-5 rotate by n*60°; 6-11 reflect first, then rotate
+func (h HexCoordinate) symmetric(n int) HexCoordinate {
+	var origin HexCoordinate
+	if n >= 6 {
+		h = h.ReflectAcross(origin)
+	}
+	return h.RotateAround(origin, n%6)
+}
+
+// CanonicalEncode describes the board the same way for every position that differs only by
+// translation, rotation or reflection: each of the twelve symmetric layouts is shifted so its
+// first cell (by row, then column) is the origin, and the smallest encoding wins
+func (b *HexBoard) CanonicalEncode() string {
+	if len(b.Pieces) == 0 {
+		return encodeStacks(b.Pieces)
+	}
+
+	best := ""
+	for n := 0; n < SymmetryCount; n++ {
+		var first HexCoordinate
+		moved := make(map[HexCoordinate][]Piece, len(b.Pieces))
+		for coord, stack := range b.Pieces {
+			coord = coord.symmetric(n)
+			if len(moved) == 0 || coord.R < first.R || (coord.R == first.R && coord.Q < first.Q) {
+				first = coord
+			}
+			moved[coord] = stack
+		}
+
+		shifted := make(map[HexCoordinate][]Piece, len(moved))
+		for coord, stack := range moved {
+			shifted[NewHexCoordinate(coord.Q-first.Q, coord.R-first.R)] = stack
+		}
+
+		if encoded := encodeStacks(shifted); n == 0 || encoded < best {
+			best = encoded
+		}
+	}
+	return best
+}
+
+// CanonicalHash returns a stable 64-bit hash that is the same for boards equal up to
+// translation, rotation and reflection. Each symmetric layout is shifted like in
+// CanonicalEncode and hashed the way the Zobrist key is, and the smallest hash wins; no
+// strings are built, so it is cheap enough to work out after every move
+func (b *HexBoard) CanonicalHash() uint64 {
+	if len(b.Pieces) == 0 {
+		return 0
+	}
+
+	coords := make([]HexCoordinate, 0, len(b.Pieces))
+	stacks := make([][]Piece, 0, len(b.Pieces))
+	for coord, stack := range b.Pieces {
+		coords = append(coords, coord)
+		stacks = append(stacks, stack)
+	}
+
+	var best uint64
+	moved := make([]HexCoordinate, len(coords))
+	for n := 0; n < SymmetryCount; n++ {
+		var first HexCoordinate
+		for i, coord := range coords {
+			moved[i] = coord.symmetric(n)
+			if i == 0 || moved[i].R < first.R || (moved[i].R == first.R && moved[i].Q < first.Q) {
+				first = moved[i]
+			}
+		}
+
+		var key uint64
+		for i, stack := range stacks {
+			shifted := NewHexCoordinate(moved[i].Q-first.Q, moved[i].R-first.R)
+			for height, piece := range stack {
+				key ^= zobristPiece(piece, shifted, height+1)
+			}
+		}
+		if n == 0 || key < best {
+			best = key
+		}
+	}
+	return best
+}
+
+// CanonicalHash hashes the position independently of where and how the hive lies on the grid,
+// with the side to move; repetitions are counted by it, so a hive that has only shifted or
+// turned is the same position
+func (g *GameState) CanonicalHash() uint64 {
+	if g.ToMove == Black {
+		return g.Board.CanonicalHash() ^ zobristBlackToMove
+	}
+	return g.Board.CanonicalHash()
+}
@@ -0,0 +1,67 @@
+package models
+
+import "testing"
+
+// transformBoard lays the board out under symmetry n and then shifts it by offset
+func transformBoard(b *HexBoard, n int, offset HexCoordinate) *HexBoard {
+	moved := NewHexBoard()
+	for coord, stack := range b.Pieces {
+		to := coord.symmetric(n).Add(offset)
+		for _, piece := range stack {
+			moved.PlacePiece(to, piece)
+		}
+	}
+	return moved
+}
+
+func TestCanonicalHashIgnoresSymmetryAndTranslation(t *testing.T) {
+	g, err := ParsePosition(roundTripPositions[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	stacked := playMoves(t, BaseRules, beetleOnStack...)
+
+	for _, board := range []*HexBoard{g.Board, stacked.Board} {
+		hash, encoded := board.CanonicalHash(), board.CanonicalEncode()
+		for n := 0; n < SymmetryCount; n++ {
+			for _, offset := range []HexCoordinate{{Q: 0, R: 0}, {Q: 3, R: -5}} {
+				moved := transformBoard(board, n, offset)
+				if got := moved.CanonicalHash(); got != hash {
+					t.Errorf("symmetry %d shifted by %v hashes to %x, want %x", n, offset, got, hash)
+				}
+				if got := moved.CanonicalEncode(); got != encoded {
+					t.Errorf("symmetry %d shifted by %v encodes as %q, want %q", n, offset, got, encoded)
+				}
+			}
+		}
+	}
+
+	// Moving a piece to a cell that is not a symmetric image changes the hash
+	other := transformBoard(stacked.Board, 0, HexCoordinate{})
+	piece, _ := other.RemovePiece(NewHexCoordinate(3, 0))
+	other.PlacePiece(NewHexCoordinate(2, -1), piece)
+	if other.CanonicalHash() == stacked.Board.CanonicalHash() {
+		t.Errorf("a different position hashes the same: %s", other.Encode())
+	}
+}
+
+func TestShiftedHiveCountsAsRepetition(t *testing.T) {
+	// Any two Queens side by side are the same position turned or shifted, so each pair of
+	// Queen steps repeats the position after both were placed
+	g := playMoves(t, BaseRules,
+		place(QueenBee, White, 0, 0, 0),
+		place(QueenBee, Black, 0, 1, 0),
+		movement(QueenBee, White, 0, 0, 0, 1, -1),
+		movement(QueenBee, Black, 0, 1, 0, 0, 0),
+		movement(QueenBee, White, 0, 1, -1, 0, -1),
+	)
+	if g.IsOver() {
+		t.Fatalf("the game ended early: %s", g.ResultReason)
+	}
+	if _, err := g.Play(movement(QueenBee, Black, 0, 0, 0, 1, -1)); err != nil {
+		t.Fatal(err)
+	}
+	if g.Result != Draw {
+		t.Errorf("the third time the position occurred the result was %s, want a draw", g.Result)
+	}
+}
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Boardspace.net archives Hive games in an SGF-like format: a list of KEY[value] properties
+// where P0 and P1 carry the actions of the first and second player, numbered in order:
+//
+//	SU[hive-plm]
+//	P0[id "alice"]
+//	P1[id "bob"]
+//	; P0[2 dropb wL N 13]
+//	; P1[3 dropb bQ N 14 wL/]
+//	; P0[8 move W wL O 15 bQ-]
+//	; P1[9 pass]
+//
+// Only the actions that change the board are replayed; picks, confirmations and the
+// like are interface events. Board cells are labelled with a column letter and a row number.
+
+// boardspaceDateLayouts are the date forms accepted from the DT property
+var boardspaceDateLayouts = []string{"2006-01-02", "2006/01/02", "2006.01.02", "Jan 2, 2006"}
+
+// boardspaceProperty is one KEY[value] pair of an SGF file
+type boardspaceProperty struct {
+	key   string
+	value string
+}
+
+// BoardspaceCoordinate maps a Boardspace cell label like N 13 to a board coordinate
+// Boardspace neighbours differ by one column, one row, or one of each in the same direction:
+// a row further is a step northeast and a column further a step southeast, so the row runs
+// along our q axis and the column along r once the row is taken out of it
+func BoardspaceCoordinate(column string, row int) (HexCoordinate, error) {
+	if len(column) != 1 || column[0] < 'A' || column[0] > 'Z' {
+		return HexCoordinate{}, fmt.Errorf("invalid column %q", column)
+	}
+	col := int(column[0] - 'A')
+	return NewHexCoordinate(row, col-row), nil
+}
+
+// ImportBoardspaceSGF reads a Boardspace game and replays every action through the rules
+// engine, returning it as a game record along with the final state
+// Errors name the Boardspace action number of the move that could not be replayed
+func ImportBoardspaceSGF(r io.Reader) (GameRecord, *GameState, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return GameRecord{}, nil, err
+	}
+	properties, err := parseBoardspaceProperties(string(data))
+	if err != nil {
+		return GameRecord{}, nil, err
+	}
+
+	rules := BaseRules
+	names := map[string]string{}
+	date := ""
+	for _, property := range properties {
+		switch {
+		case property.key == "SU":
+			rules, err = boardspaceRules(property.value)
+			if err != nil {
+				return GameRecord{}, nil, err
+			}
+		case property.key == "DT":
+			date = property.value
+		case (property.key == "P0" || property.key == "P1") && strings.HasPrefix(property.value, "id "):
+			name := strings.TrimSpace(property.value[3:])
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+			names[property.key] = name
+		}
+	}
+
+	importer := boardspaceImporter{state: NewGameState(rules), players: map[PieceColor]string{}}
+	for _, property := range properties {
+		if property.key != "P0" && property.key != "P1" {
+			continue
+		}
+		if err := importer.apply(property); err != nil {
+			return GameRecord{}, nil, err
+		}
+	}
+
+	state := importer.state
+	record := NewGameRecord(state, names[importer.players[White]], names[importer.players[Black]], time.Time{})
+	delete(record.Headers, "Date")
+	for _, layout := range boardspaceDateLayouts {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			record.Headers["Date"] = parsed.Format("2006.01.02")
+			break
+		}
+	}
+	return record, state, nil
+}
+
+// LoadBoardspaceSGF imports a Boardspace game from a file
+func LoadBoardspaceSGF(path string) (GameRecord, *GameState, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return GameRecord{}, nil, err
+	}
+	defer file.Close()
+
+	record, state, err := ImportBoardspaceSGF(file)
+	if err != nil {
+		return record, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return record, state, nil
+}
+
+// parseBoardspaceProperties splits a file into its KEY[value] properties, in order
+// Values are taken verbatim up to the closing bracket: Boardspace does not escape the
+// backslashes of its move notation, so neither does the reader
+func parseBoardspaceProperties(text string) ([]boardspaceProperty, error) {
+	properties := []boardspaceProperty{}
+	key := ""
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' && key != "":
+			key += string(c)
+		case c == '[':
+			end := strings.IndexByte(text[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("property %s is missing its closing bracket", key)
+			}
+			properties = append(properties, boardspaceProperty{key: key, value: strings.TrimSpace(text[i+1 : i+end])})
+			key = ""
+			i += end
+		default:
+			key = ""
+		}
+	}
+	return properties, nil
+}
+
+// boardspaceRules reads the game type of the SU property: hive, hive-m, hive-plm, hive-ultimate...
+func boardspaceRules(variant string) (RuleSet, error) {
+	variant = strings.ToLower(variant)
+	if !strings.HasPrefix(variant, "hive") {
+		return RuleSet{}, fmt.Errorf("not a Hive game: %s", variant)
+	}
+
+	rules := BaseRules
+	_, expansions, _ := strings.Cut(variant, "-")
+	if expansions == "ultimate" {
+		return RuleSet{Mosquito: true, Ladybug: true, Pillbug: true}, nil
+	}
+	for _, letter := range expansions {
+		switch letter {
+		case 'm':
+			rules.Mosquito = true
+		case 'l':
+			rules.Ladybug = true
+		case 'p':
+			rules.Pillbug = true
+		default:
+			return RuleSet{}, fmt.Errorf("unknown Hive variant: %s", variant)
+		}
+	}
+	return rules, nil
+}
+
+// boardspaceImporter replays Boardspace actions, moving the first piece to the origin
+type boardspaceImporter struct {
+	state     *GameState
+	origin    HexCoordinate
+	hasOrigin bool
+	players   map[PieceColor]string // Property of the player holding each colour
+}
+
+// apply replays one P0 or P1 action
+func (im *boardspaceImporter) apply(property boardspaceProperty) error {
+	fields := strings.Fields(property.value)
+	if len(fields) < 2 {
+		return nil
+	}
+	number, args := fields[0], fields[2:]
+
+	var move Move
+	switch strings.ToLower(fields[1]) {
+	case "dropb", "pdropb", "move", "pmove":
+		// Movements name the player's colour before the piece: move W wA1 N 13 bQ-
+		if len(args) > 0 && (args[0] == "W" || args[0] == "B") {
+			args = args[1:]
+		}
+		parsed, ok, err := im.parseMove(args)
+		if err != nil {
+			return fmt.Errorf("move %s: %s: %s", number, property.value, err)
+		}
+		if !ok {
+			return nil
+		}
+		move = parsed
+	case "pass":
+		move = Move{Type: PassMove}
+	default:
+		return nil
+	}
+
+	if move.Type == PlaceMove {
+		if _, seen := im.players[move.Piece.Color]; !seen {
+			im.players[move.Piece.Color] = property.key
+		}
+	}
+	if _, err := im.state.Play(move); err != nil {
+		return fmt.Errorf("move %s: illegal move %q: %s", number, property.value, err)
+	}
+	return nil
+}
+
+// parseMove turns "wA1 N 13 [notation]" into a placement or movement
+// Returns false for a piece put back where it was picked up
+func (im *boardspaceImporter) parseMove(args []string) (Move, bool, error) {
+	if len(args) < 3 {
+		return Move{}, false, fmt.Errorf("expected a piece and a cell")
+	}
+
+	piece, err := ParseNotationPiece(args[0])
+	if err != nil {
+		return Move{}, false, err
+	}
+	// Boardspace sometimes numbers single pieces, e.g. wQ1
+	if info, ok := GetPieceInfo(piece.Type); ok && info.Quantity == 1 {
+		piece.Number = 0
+	}
+
+	row, err := strconv.Atoi(args[2])
+	if err != nil {
+		return Move{}, false, fmt.Errorf("invalid row %q", args[2])
+	}
+	to, err := BoardspaceCoordinate(strings.ToUpper(args[1]), row)
+	if err != nil {
+		return Move{}, false, err
+	}
+	if !im.hasOrigin {
+		im.origin, im.hasOrigin = to, true
+	}
+	to = NewHexCoordinate(to.Q-im.origin.Q, to.R-im.origin.R)
+
+	if from, onBoard := im.state.Board.FindPiece(piece); onBoard {
+		if from.Equals(to) {
+			return Move{}, false, nil
+		}
+		return Move{Type: MovementMove, Piece: piece, From: from, To: to}, true, nil
+	}
+	return Move{Type: PlaceMove, Piece: piece, To: to}, true, nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// boardspaceGame is a short Boardspace game; each action ends with Boardspace's own notation
+// for it, which the import must reproduce
+const boardspaceGame = `(;
+SU[hive-l]
+P0[id "alice"]
+P1[id "bob"]
+DT[2024-03-09]
+; P0[2 dropb wL N 13]
+; P1[3 dropb bQ N 14 wL/]
+; P0[4 dropb wQ M 12 -wL]
+; P1[5 dropb bA1 O 15 bQ-]
+; P0[6 pick W wQ]
+; P0[7 move W wQ M 13 -bQ]
+; P1[8 dropb bG1 P 15 bA1\]
+)`
+
+func TestImportBoardspaceNotation(t *testing.T) {
+	record, state, err := ImportBoardspaceSGF(strings.NewReader(boardspaceGame))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"wL", "bQ wL/", "wQ -wL", "bA1 bQ-", "wQ -bQ", "bG1 bA1\\"}
+	if !reflect.DeepEqual(record.Moves, want) {
+		t.Errorf("imported moves %q, want Boardspace's %q", record.Moves, want)
+	}
+	if record.Headers["White"] != "alice" || record.Headers["Black"] != "bob" || record.Headers["Date"] != "2024.03.09" {
+		t.Errorf("imported headers %v", record.Headers)
+	}
+
+	// Boardspace's notation played from the start gives the same board as its cells
+	replayed, err := GameRecord{Headers: record.Headers, Moves: want}.Replay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayed.Board.Pieces, state.Board.Pieces) {
+		t.Errorf("the notation gives board %s, the cells %s", replayed.Board.Encode(), state.Board.Encode())
+	}
+}
+
+func TestBoardspaceCoordinateNeighbours(t *testing.T) {
+	origin, _ := BoardspaceCoordinate("N", 13)
+	neighbours := []struct {
+		column string
+		row    int
+		dir    int
+	}{{"O", 14, 0}, {"N", 14, 1}, {"M", 13, 2}, {"M", 12, 3}, {"N", 12, 4}, {"O", 13, 5}}
+	for _, n := range neighbours {
+		coord, err := BoardspaceCoordinate(n.column, n.row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := origin.DirectionTo(coord); got != n.dir {
+			t.Errorf("%s %d lies in direction %d from N 13, want %d", n.column, n.row, got, n.dir)
+		}
+	}
+	if _, err := BoardspaceCoordinate("n1", 13); err == nil {
+		t.Errorf("column n1 was accepted")
+	}
+}
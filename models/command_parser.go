@@ -12,6 +12,19 @@ type CommandType int
 const (
 	PlaceCommand CommandType = iota
 	MoveCommand
+	PassCommand
+	NewGameCommand
+	UndoCommand
+	RedoCommand
+	QuitCommand
+	LimitCommand
+	PerftCommand
+	SaveCommand
+	LoadCommand
+	PositionCommand
+	SetPositionCommand
+	ReplayCommand
+	EvalCommand
 	InvalidCommand
 )
 
@@ -21,6 +34,8 @@ type Command struct {
 	Piece     string
 	FromCoord HexCoordinate
 	ToCoord   HexCoordinate
+	Value     int    // Numeric argument, e.g. the turn count of a limit command
+	Argument  string // Text argument, e.g. the file of a save command
 	Error     string
 }
 
@@ -43,6 +58,30 @@ func ParseCommand(input string) Command {
 		return parsePlaceCommand(parts)
 	case "move":
 		return parseMoveCommand(parts)
+	case "pass":
+		return Command{Type: PassCommand}
+	case "new":
+		return Command{Type: NewGameCommand}
+	case "undo":
+		return Command{Type: UndoCommand}
+	case "redo":
+		return Command{Type: RedoCommand}
+	case "quit":
+		return Command{Type: QuitCommand}
+	case "limit":
+		return parseLimitCommand(parts)
+	case "perft":
+		return parsePerftCommand(parts)
+	case "save", "load":
+		return parseFileCommand(cmdType, parts)
+	case "position":
+		return Command{Type: PositionCommand}
+	case "setposition":
+		return parseSetPositionCommand(parts)
+	case "replay":
+		return Command{Type: ReplayCommand, Argument: strings.Join(parts[1:], " ")}
+	case "eval":
+		return Command{Type: EvalCommand, Argument: strings.Join(parts[1:], " ")}
 	default:
 		return Command{
 			Type:  InvalidCommand,
@@ -51,6 +90,81 @@ func ParseCommand(input string) Command {
 	}
 }
 
+// ParseCommandOnBoard parses a user input string like ParseCommand, and also accepts
+// standard Hive notation resolved against the board: "wS1 bG1-" moves or places wS1
+// to the right of bG1, and "bB1 wQ" climbs on top of wQ
+func ParseCommandOnBoard(input string, board *HexBoard) Command {
+	command := ParseCommand(input)
+	if command.Type != InvalidCommand {
+		return command
+	}
+	
+	// Anything starting with a piece name is taken as notation
+	parts := strings.Fields(input)
+	if len(parts) == 0 {
+		return command
+	}
+	if _, err := ParseNotationPiece(parts[0]); err != nil {
+		return command
+	}
+	
+	return parseNotationCommand(parts, board)
+}
+
+// parseNotationCommand parses a move in standard notation
+// Format: <piece> [<reference>]
+// Example: wS1 bG1- (placement or movement, depending on whether wS1 is on the board)
+func parseNotationCommand(parts []string, board *HexBoard) Command {
+	if len(parts) == 0 || len(parts) > 2 {
+		return Command{
+			Type:  InvalidCommand,
+			Error: "Notation format: <piece> <reference>, e.g. wS1 bG1-",
+		}
+	}
+	
+	piece, err := ParseNotationPiece(parts[0])
+	if err != nil {
+		return Command{
+			Type:  InvalidCommand,
+			Error: fmt.Sprintf("Invalid piece: %s", parts[0]),
+		}
+	}
+	
+	// Only the very first piece of the game needs no reference
+	to := NewHexCoordinate(0, 0)
+	if len(parts) == 1 {
+		if board.PieceCount() > 0 {
+			return Command{
+				Type:  InvalidCommand,
+				Error: fmt.Sprintf("%s needs a reference piece to say where it goes", parts[0]),
+			}
+		}
+	} else {
+		to, err = board.ResolveReference(parts[1])
+		if err != nil {
+			return Command{
+				Type:  InvalidCommand,
+				Error: err.Error(),
+			}
+		}
+	}
+	
+	if from, onBoard := board.FindPiece(piece); onBoard {
+		return Command{
+			Type:      MoveCommand,
+			Piece:     piece.String(),
+			FromCoord: from,
+			ToCoord:   to,
+		}
+	}
+	
+	return Command{
+		Type:    PlaceCommand,
+		Piece:   piece.String(),
+		ToCoord: to,
+	}
+}
+
 // parsePlaceCommand parses a place command
 // Format: place <piece> <q> <r>
 // Example: place WQ 0 0
@@ -84,7 +198,7 @@ func parsePlaceCommand(parts []string) Command {
 	if !isValidPiece(piece) {
 		return Command{
 			Type:  InvalidCommand,
-			Error: fmt.Sprintf("Invalid piece: %s. Use format like WQ, BA1, WS2", piece),
+			Error: fmt.Sprintf("Invalid piece: %s. Use format like WQ, BA1, WS2, WM", piece),
 		}
 	}
 	
@@ -148,6 +262,94 @@ func parseMoveCommand(parts []string) Command {
 	}
 }
 
+// parseLimitCommand parses a move limit command
+// Format: limit <turns>
+// Example: limit 50 (use 0 to remove the limit)
+func parseLimitCommand(parts []string) Command {
+	if len(parts) < 2 {
+		return Command{
+			Type:  InvalidCommand,
+			Error: "Limit command format: limit <turns>",
+		}
+	}
+	
+	turns, err := strconv.Atoi(parts[1])
+	if err != nil || turns < 0 {
+		return Command{
+			Type:  InvalidCommand,
+			Error: fmt.Sprintf("Invalid turn count: %s", parts[1]),
+		}
+	}
+	
+	return Command{
+		Type:  LimitCommand,
+		Value: turns,
+	}
+}
+
+// parsePerftCommand parses a perft command
+// Format: perft <depth>
+// Example: perft 3
+func parsePerftCommand(parts []string) Command {
+	if len(parts) < 2 {
+		return Command{
+			Type:  InvalidCommand,
+			Error: "Perft command format: perft <depth>",
+		}
+	}
+	
+	depth, err := strconv.Atoi(parts[1])
+	if err != nil || depth < 1 {
+		return Command{
+			Type:  InvalidCommand,
+			Error: fmt.Sprintf("Invalid depth: %s", parts[1]),
+		}
+	}
+	
+	return Command{
+		Type:  PerftCommand,
+		Value: depth,
+	}
+}
+
+// parseFileCommand parses a save or load command
+// Format: save <file> | load <file>
+// Example: save mygame.hive
+func parseFileCommand(cmdType string, parts []string) Command {
+	if len(parts) < 2 {
+		return Command{
+			Type:  InvalidCommand,
+			Error: fmt.Sprintf("%s%s command format: %s <file>", strings.ToUpper(cmdType[:1]), cmdType[1:], cmdType),
+		}
+	}
+	
+	command := Command{
+		Type:     SaveCommand,
+		Argument: strings.Join(parts[1:], " "),
+	}
+	if cmdType == "load" {
+		command.Type = LoadCommand
+	}
+	return command
+}
+
+// parseSetPositionCommand parses a setposition command
+// Format: setposition <position string>
+// Example: setposition Base - b 1 0,0:wS1 Q0A3G3S1B2/Q1A3G3S2B2 wS1
+func parseSetPositionCommand(parts []string) Command {
+	if len(parts) < 2 {
+		return Command{
+			Type:  InvalidCommand,
+			Error: "Setposition command format: setposition <position>",
+		}
+	}
+	
+	return Command{
+		Type:     SetPositionCommand,
+		Argument: strings.Join(parts[1:], " "),
+	}
+}
+
 // isValidPiece checks if a piece string is valid
 // Valid formats: WQ, BA1, WS2, etc.
 func isValidPiece(piece string) bool {
@@ -161,17 +363,9 @@ func isValidPiece(piece string) bool {
 		return false
 	}
 	
-	// Second character must be a valid piece type
-	pieceType := piece[1]
-	validTypes := map[byte]bool{
-		'Q': true, // Queen
-		'A': true, // Ant
-		'G': true, // Grasshopper
-		'S': true, // Spider
-		'B': true, // Beetle
-	}
-	
-	if !validTypes[pieceType] {
+	// Second character must be a known piece type, expansions included
+	// (whether the game uses that expansion is checked by the game state)
+	if _, known := GetPieceInfo(PieceType(piece[1])); !known {
 		return false
 	}
 	
@@ -198,19 +392,7 @@ func ParsePieceString(pieceStr string) (Piece, error) {
 		color = Black
 	}
 	
-	var pieceType PieceType
-	switch pieceStr[1] {
-	case 'Q':
-		pieceType = QueenBee
-	case 'A':
-		pieceType = Ant
-	case 'G':
-		pieceType = Grasshopper
-	case 'S':
-		pieceType = Spider
-	case 'B':
-		pieceType = Beetle
-	}
+	pieceType := PieceType(pieceStr[1])
 	
 	number := 0
 	if len(pieceStr) == 3 {
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Difficulty is how strongly the computer opponent plays
+type Difficulty int
+
+const (
+	Beginner Difficulty = iota
+	Easy
+	Medium
+	Hard
+	Expert
+)
+
+// Difficulties lists the levels from weakest to strongest
+var Difficulties = []Difficulty{Beginner, Easy, Medium, Hard, Expert}
+
+// difficultyLevel is how a level holds the engine back
+type difficultyLevel struct {
+	name        string
+	description string
+	limits      SearchLimits
+	noise       int     // Largest error added to each evaluation
+	blunder     float64 // Chance of a random move instead of a searched one
+}
+
+var difficultyLevels = map[Difficulty]difficultyLevel{
+	Beginner: {"Beginner", "Looks one move ahead and often plays at random", SearchLimits{Depth: 1}, 300, 0.3},
+	Easy:     {"Easy", "Looks two moves ahead and misjudges positions", SearchLimits{Depth: 2}, 150, 0.15},
+	Medium:   {"Medium", "Looks three moves ahead, with the odd slip", SearchLimits{Depth: 3}, 60, 0.05},
+	Hard:     {"Hard", "Thinks for two seconds a move", SearchLimits{Time: 2 * time.Second}, 20, 0},
+	Expert:   {"Expert", "Thinks for five seconds a move, flawlessly", SearchLimits{Time: 5 * time.Second}, 0, 0},
+}
+
+// String returns the level's name, as shown in the interface and saved games
+func (d Difficulty) String() string {
+	if level, ok := difficultyLevels[d]; ok {
+		return level.name
+	}
+	return fmt.Sprintf("Difficulty(%d)", int(d))
+}
+
+// Description says in a few words how the level plays
+func (d Difficulty) Description() string {
+	return difficultyLevels[d].description
+}
+
+// Limits returns how long the computer thinks at this level
+func (d Difficulty) Limits() SearchLimits {
+	return difficultyLevels[d].limits
+}
+
+// NewEngine creates an alpha-beta engine that plays at this level
+func (d Difficulty) NewEngine() *AlphaBetaEngine {
+	engine := NewAlphaBetaEngine()
+	engine.Noise = difficultyLevels[d].noise
+	engine.Blunder = difficultyLevels[d].blunder
+	return engine
+}
+
+// ParseDifficulty reads a level by name, in any case
+func ParseDifficulty(s string) (Difficulty, error) {
+	for _, d := range Difficulties {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return Medium, fmt.Errorf("unknown difficulty: %s", s)
+}
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A game record is a plain text file: a header block of [Name "Value"] tags describing
+// the game, followed by one numbered move per line in standard notation:
+//
+//	[GameType "Base+M"]
+//	[White "Player 1"]
+//	[Result "InProgress"]
+//
+//	1. wS1
+//	2. bG1 -wS1
+//
+// Blank lines and lines starting with # are ignored.
+
+// recordHeaderOrder is the order in which the well-known headers are written
+var recordHeaderOrder = []string{"Game", "GameType", "TournamentOpening", "MoveLimit", "Position", "White", "Black", "Difficulty", "Date", "Result"}
+
+// GameRecord holds a game as read from or written to a record file
+type GameRecord struct {
+	Headers    map[string]string
+	Moves      []string
+	moveLines  []int // Line of each move in the file it was read from
+	resultLine int
+}
+
+// NewGameRecord describes a game with its players and the date it was played
+// Games set up from a position string keep it in a Position header, since the moves start there
+func NewGameRecord(state *GameState, white, black string, date time.Time) GameRecord {
+	record := GameRecord{
+		Headers: map[string]string{
+			"Game":              "Hive",
+			"GameType":          state.Rules.String(),
+			"TournamentOpening": uhpBool(state.Rules.TournamentOpening),
+			"MoveLimit":         strconv.Itoa(state.MoveLimit),
+			"White":             white,
+			"Black":             black,
+			"Date":              date.Format("2006.01.02"),
+			"Result":            uhpGameState(state),
+		},
+		Moves: state.MoveStrings(),
+	}
+	if position := state.StartPosition(); position != "" {
+		record.Headers["Position"] = position
+	}
+	return record
+}
+
+// Write writes the record in its text form
+func (r GameRecord) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	written := make(map[string]bool)
+	for _, name := range recordHeaderOrder {
+		if value, ok := r.Headers[name]; ok {
+			fmt.Fprintf(bw, "[%s %q]\n", name, value)
+			written[name] = true
+		}
+	}
+
+	// Any extra headers follow in alphabetical order so files stay stable
+	extra := []string{}
+	for name := range r.Headers {
+		if !written[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		fmt.Fprintf(bw, "[%s %q]\n", name, r.Headers[name])
+	}
+
+	fmt.Fprintln(bw)
+	for i, move := range r.Moves {
+		fmt.Fprintf(bw, "%d. %s\n", i+1, move)
+	}
+
+	return bw.Flush()
+}
+
+// ReadGameRecord parses a record, reporting syntax errors with their line number
+func ReadGameRecord(r io.Reader) (GameRecord, error) {
+	record := GameRecord{Headers: make(map[string]string)}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if len(record.Moves) > 0 {
+				return record, fmt.Errorf("line %d: header after the first move", lineNumber)
+			}
+			name, value, err := parseRecordHeader(line)
+			if err != nil {
+				return record, fmt.Errorf("line %d: %s", lineNumber, err)
+			}
+			record.Headers[name] = value
+			if name == "Result" {
+				record.resultLine = lineNumber
+			}
+			continue
+		}
+
+		number, move, found := strings.Cut(line, ".")
+		n, err := strconv.Atoi(number)
+		if !found || err != nil {
+			return record, fmt.Errorf("line %d: expected a numbered move like \"3. wA1 bQ-\"", lineNumber)
+		}
+		if n != len(record.Moves)+1 {
+			return record, fmt.Errorf("line %d: expected move %d, found %d", lineNumber, len(record.Moves)+1, n)
+		}
+		record.Moves = append(record.Moves, strings.TrimSpace(move))
+		record.moveLines = append(record.moveLines, lineNumber)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+// parseRecordHeader splits a [Name "Value"] header line
+func parseRecordHeader(line string) (string, string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("header is missing its closing bracket")
+	}
+	name, quoted, found := strings.Cut(strings.TrimSpace(line[1:len(line)-1]), " ")
+	if !found || name == "" {
+		return "", "", fmt.Errorf("header must look like [Name \"Value\"]")
+	}
+	value, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return "", "", fmt.Errorf("header %s has an invalid value %s", name, quoted)
+	}
+	return name, value, nil
+}
+
+// Replay plays the recorded moves through the rules engine from an empty board, or from
+// the Position header when there is one
+// Illegal moves, or a recorded result that does not match the game, are reported with their line
+func (r GameRecord) Replay() (*GameState, error) {
+	rules := BaseRules
+	if gameType, ok := r.Headers["GameType"]; ok {
+		parsed, err := ParseRuleSet(gameType)
+		if err != nil {
+			return nil, err
+		}
+		rules = parsed
+	}
+	if value, ok := r.Headers["TournamentOpening"]; ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TournamentOpening header: %s", value)
+		}
+		rules.TournamentOpening = enabled
+	}
+
+	state := NewGameState(rules)
+	if position, ok := r.Headers["Position"]; ok {
+		parsed, err := ParsePosition(position)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Position header: %s", err)
+		}
+		state = parsed
+	}
+	if value, ok := r.Headers["MoveLimit"]; ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid MoveLimit header: %s", value)
+		}
+		state.MoveLimit = limit
+	}
+
+	for i, moveString := range r.Moves {
+		move, err := state.ParseMoveString(moveString)
+		if err == nil {
+			_, err = state.Play(move)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: illegal move %q: %s", r.lineOf(i), moveString, err)
+		}
+	}
+
+	if result, ok := r.Headers["Result"]; ok && result != uhpGameState(state) {
+		return nil, fmt.Errorf("line %d: recorded result %s does not match the game (%s)", r.resultLine, result, uhpGameState(state))
+	}
+
+	return state, nil
+}
+
+// lineOf returns the file line of the ith move, or its move number for records built in memory
+func (r GameRecord) lineOf(i int) int {
+	if i < len(r.moveLines) {
+		return r.moveLines[i]
+	}
+	return i + 1
+}
+
+// SaveGameRecord writes a record to a file
+func SaveGameRecord(path string, record GameRecord) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := record.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadGameRecord reads a record from a file and replays it
+func LoadGameRecord(path string) (GameRecord, *GameState, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return GameRecord{}, nil, err
+	}
+	defer file.Close()
+
+	record, err := ReadGameRecord(file)
+	if err != nil {
+		return record, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	state, err := record.Replay()
+	if err != nil {
+		return record, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return record, state, nil
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"fmt"
+)
+
+// GameResult represents whether a game is still being played and who won it
+type GameResult int
+
+const (
+	InProgress GameResult = iota
+	WhiteWins
+	BlackWins
+	Draw
+)
+
+// String returns a human readable description of the result
+func (r GameResult) String() string {
+	switch r {
+	case WhiteWins:
+		return "White wins"
+	case BlackWins:
+		return "Black wins"
+	case Draw:
+		return "Draw"
+	}
+	return "In progress"
+}
+
+// winFor returns the result in which the given colour wins
+func winFor(color PieceColor) GameResult {
+	if color == Black {
+		return BlackWins
+	}
+	return WhiteWins
+}
+
+// RepetitionLimit is how many times the same position may occur before the game is drawn
+const RepetitionLimit = 3
+
+// IsOver reports whether the game has been decided
+func (g *GameState) IsOver() bool {
+	return g.Result != InProgress
+}
+
+// updateResult decides the game after a move: surrounded Queens first, then draws
+func (g *GameState) updateResult() {
+	whiteLost := g.queenSurrounded(White)
+	blackLost := g.queenSurrounded(Black)
+
+	switch {
+	case whiteLost && blackLost:
+		g.Result, g.ResultReason = Draw, "both Queens are surrounded"
+	case whiteLost:
+		g.Result, g.ResultReason = BlackWins, "White's Queen is surrounded"
+	case blackLost:
+		g.Result, g.ResultReason = WhiteWins, "Black's Queen is surrounded"
+	case g.positions[g.positionKey()] >= RepetitionLimit:
+		g.Result, g.ResultReason = Draw, "threefold repetition"
+	case g.MoveLimit > 0 && g.Ply >= 2*g.MoveLimit:
+		g.Result, g.ResultReason = Draw, fmt.Sprintf("move limit of %d turns reached", g.MoveLimit)
+	}
+}
+
+// queenSurrounded reports whether the player's Queen is on the board with all six sides covered
+func (g *GameState) queenSurrounded(color PieceColor) bool {
+	coord, found := g.Board.FindPiece(NewPiece(QueenBee, color, 0))
+	return found && g.Board.IsSurrounded(coord)
+}
+
+// positionKey identifies the position for repetition detection: the canonical hash, so a hive
+// that has only shifted or turned counts as the same position, with the side to move
+// The reserves follow from the board, so they do not need to be part of the key
+func (g *GameState) positionKey() uint64 {
+	return g.CanonicalHash()
+}
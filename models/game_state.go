@@ -0,0 +1,375 @@
+package models
+
+import (
+	"fmt"
+)
+
+// MoveType represents the different kinds of turn a player can take
+type MoveType int
+
+const (
+	PlaceMove MoveType = iota
+	MovementMove
+	PassMove
+)
+
+// Move represents a single turn: a piece entering from the reserve, moving on the board,
+// or a pass when the player has nothing else to do
+type Move struct {
+	Type  MoveType
+	Piece Piece
+	From  HexCoordinate // Only used by movements
+	To    HexCoordinate
+}
+
+// String returns the move in the same form as the place/move commands
+func (m Move) String() string {
+	switch m.Type {
+	case PlaceMove:
+		return fmt.Sprintf("place %s %d %d", m.Piece, m.To.Q, m.To.R)
+	case PassMove:
+		return "pass"
+	}
+	return fmt.Sprintf("move %s %d %d %d %d", m.Piece, m.From.Q, m.From.R, m.To.Q, m.To.R)
+}
+
+// GameState tracks everything about a Hive game beyond the board itself:
+// whose turn it is, how many turns were played and what each player still holds
+type GameState struct {
+	Rules        RuleSet
+	Board        *HexBoard
+	ToMove       PieceColor
+	Ply          int // Number of turns taken so far by both players together
+	MoveLimit    int // Turns after which the game is drawn, 0 for no limit
+	Result       GameResult
+	ResultReason string
+	reserves     map[PieceColor]map[PieceType]int
+	history      []Move
+	redo         []Move         // Moves taken back by Undo, the next one to redo last
+	positions    map[uint64]int // How often each position has occurred
+
+	// A game set up from a position string starts from that position instead of an empty board
+	startPosition     string
+	startLastMoved    Piece
+	hasStartLastMoved bool
+}
+
+// NewGameState creates a new game with an empty board and full reserves, White to move
+// The rule set decides which expansion pieces are in the reserves
+func NewGameState(rules RuleSet) *GameState {
+	reserves := make(map[PieceColor]map[PieceType]int)
+	for _, color := range []PieceColor{White, Black} {
+		reserves[color] = make(map[PieceType]int)
+		for _, info := range rules.PieceTypes() {
+			reserves[color][info.Symbol] = info.Quantity
+		}
+	}
+
+	g := &GameState{
+		Rules:     rules,
+		Board:     NewHexBoard(),
+		ToMove:    White,
+		Ply:       0,
+		reserves:  reserves,
+		positions: make(map[uint64]int),
+	}
+	g.positions[g.positionKey()]++
+	return g
+}
+
+// Turn returns the game turn number, counting one turn for each White and Black pair
+// Because White always moves first, it is also how many turns the side to move has started
+func (g *GameState) Turn() int {
+	return g.Ply/2 + 1
+}
+
+// Remaining returns how many pieces of the given type a player still holds
+func (g *GameState) Remaining(color PieceColor, pieceType PieceType) int {
+	return g.reserves[color][pieceType]
+}
+
+// Reserve returns every piece still in the player's hand, in placement order
+func (g *GameState) Reserve(color PieceColor) []Piece {
+	pieces := []Piece{}
+	for _, info := range g.Rules.PieceTypes() {
+		remaining := g.reserves[color][info.Symbol]
+		for i := info.Quantity - remaining + 1; i <= info.Quantity; i++ {
+			pieces = append(pieces, g.numberedPiece(info, color, i))
+		}
+	}
+	return pieces
+}
+
+// NextPiece returns the next piece of a type that would come out of the player's reserve
+func (g *GameState) NextPiece(color PieceColor, pieceType PieceType) (Piece, bool) {
+	info, ok := GetPieceInfo(pieceType)
+	if !ok || g.reserves[color][pieceType] == 0 {
+		return Piece{}, false
+	}
+	return g.numberedPiece(info, color, info.Quantity-g.reserves[color][pieceType]+1), true
+}
+
+// numberedPiece builds the nth piece of a type, leaving single pieces like the Queen unnumbered
+func (g *GameState) numberedPiece(info PieceInfo, color PieceColor, n int) Piece {
+	if info.Quantity == 1 {
+		return NewPiece(info.Symbol, color, 0)
+	}
+	return NewPiece(info.Symbol, color, n)
+}
+
+// QueenPlaced reports whether the player's Queen Bee is on the board
+func (g *GameState) QueenPlaced(color PieceColor) bool {
+	return g.reserves[color][QueenBee] == 0
+}
+
+// checkQueenTiming enforces when the Queen may be placed and when it must be
+func (g *GameState) checkQueenTiming(piece Piece) error {
+	if piece.Type == QueenBee && g.Rules.TournamentOpening && g.Turn() == 1 {
+		return fmt.Errorf("the Queen cannot be placed on the first turn (Tournament Opening)")
+	}
+	if piece.Type != QueenBee && g.Turn() >= 4 && !g.QueenPlaced(piece.Color) {
+		return fmt.Errorf("%s must place the Queen by the fourth turn", piece.Color.Name())
+	}
+	return nil
+}
+
+// Validate checks a move against the turn order, the reserves and the board rules
+// Returns nil when the move is legal, otherwise an error describing the broken rule
+func (g *GameState) Validate(move Move) error {
+	if g.IsOver() {
+		return fmt.Errorf("the game is over: %s", g.Result)
+	}
+	if move.Type == PassMove {
+		if g.HasLegalMove() {
+			return fmt.Errorf("%s has a legal move and cannot pass", g.ToMove.Name())
+		}
+		return nil
+	}
+
+	switch move.Type {
+	case PlaceMove:
+		if move.Piece.Color != g.ToMove {
+			return fmt.Errorf("it is %s's turn", g.ToMove.Name())
+		}
+		return g.validatePlacement(move)
+	case MovementMove:
+		return g.validateMovement(move)
+	}
+
+	return fmt.Errorf("unknown move type")
+}
+
+// validateMovement checks a movement of the player's own piece, or of any piece
+// moved by the player's pillbug
+func (g *GameState) validateMovement(move Move) error {
+	if !g.QueenPlaced(g.ToMove) {
+		return fmt.Errorf("%s cannot move pieces before placing the Queen", g.ToMove.Name())
+	}
+	if last, ok := g.lastMovedPiece(); ok && last == move.Piece {
+		return fmt.Errorf("%s was just moved and cannot move this turn", move.Piece)
+	}
+
+	if g.isThrow(move) {
+		return nil
+	}
+	if move.Piece.Color != g.ToMove {
+		if g.Rules.Pillbug {
+			return fmt.Errorf("%s belongs to %s and can only be moved by your Pillbug", move.Piece, move.Piece.Color.Name())
+		}
+		return fmt.Errorf("it is %s's turn", g.ToMove.Name())
+	}
+	return ValidateMovement(g.Board, move.Piece, move.From, move.To)
+}
+
+// lastMovedPiece returns the piece placed or moved on the previous turn
+// That piece can be neither moved nor thrown by a pillbug this turn
+func (g *GameState) lastMovedPiece() (Piece, bool) {
+	if len(g.history) == 0 {
+		return g.startLastMoved, g.hasStartLastMoved
+	}
+	last := g.history[len(g.history)-1]
+	if last.Type == PassMove {
+		return Piece{}, false
+	}
+	return last.Piece, true
+}
+
+// isThrow reports whether a movement is one of the side to move's pillbug throws
+func (g *GameState) isThrow(move Move) bool {
+	for _, throw := range g.throwMoves() {
+		if throw == move {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePlacement checks the reserve and Queen deadline before the board placement rules
+func (g *GameState) validatePlacement(move Move) error {
+	piece := move.Piece
+	if !g.Rules.Allows(piece.Type) {
+		info, _ := GetPieceInfo(piece.Type)
+		return fmt.Errorf("the %s is not part of this game (%s)", info.Name, g.Rules)
+	}
+	next, ok := g.NextPiece(piece.Color, piece.Type)
+	if !ok {
+		info, _ := GetPieceInfo(piece.Type)
+		return fmt.Errorf("%s has no %s left to place", piece.Color.Name(), info.Name)
+	}
+	if piece != next {
+		return fmt.Errorf("%s is not in the reserve, the next one to place is %s", piece, next)
+	}
+
+	if err := g.checkQueenTiming(piece); err != nil {
+		return err
+	}
+
+	return ValidatePlacement(g.Board, piece, move.To)
+}
+
+// Play validates a move and applies it, handing the turn to the other player
+// A placement with an unnumbered piece (e.g. WA) takes the next one from the reserve
+func (g *GameState) Play(move Move) (Move, error) {
+	if move.Type == PlaceMove && move.Piece.Number == 0 {
+		if next, ok := g.NextPiece(move.Piece.Color, move.Piece.Type); ok {
+			move.Piece = next
+		}
+	}
+
+	if err := g.Validate(move); err != nil {
+		return move, err
+	}
+
+	g.apply(move)
+	g.redo = nil
+	return move, nil
+}
+
+// apply plays a move that is already known to be legal and hands the turn to the other player
+func (g *GameState) apply(move Move) {
+	switch move.Type {
+	case PlaceMove:
+		g.reserves[move.Piece.Color][move.Piece.Type]--
+		g.Board.PlacePiece(move.To, move.Piece)
+	case MovementMove:
+		piece, _ := g.Board.RemovePiece(move.From)
+		g.Board.PlacePiece(move.To, piece)
+	}
+
+	g.Ply++
+	g.ToMove = g.ToMove.Opponent()
+	g.history = append(g.history, move)
+	g.positions[g.positionKey()]++
+	g.updateResult()
+}
+
+// Undo takes back the last move, returning pieces to where they came from
+// Any result reached by that move is cleared, since the game was still running before it
+// The move can be played again with Redo until a different move is played
+func (g *GameState) Undo() (Move, bool) {
+	move, ok := g.undo()
+	if ok {
+		g.redo = append(g.redo, move)
+	}
+	return move, ok
+}
+
+// Redo plays the last undone move again
+func (g *GameState) Redo() (Move, bool) {
+	move, ok := g.NextRedo()
+	if !ok {
+		return Move{}, false
+	}
+	g.redo = g.redo[:len(g.redo)-1]
+	g.apply(move)
+	return move, true
+}
+
+// NextRedo returns the move Redo would play, if any
+func (g *GameState) NextRedo() (Move, bool) {
+	if len(g.redo) == 0 {
+		return Move{}, false
+	}
+	return g.redo[len(g.redo)-1], true
+}
+
+// undo takes back the last move without offering it for Redo, as searches do
+func (g *GameState) undo() (Move, bool) {
+	if len(g.history) == 0 {
+		return Move{}, false
+	}
+
+	move := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+	if key := g.positionKey(); g.positions[key] > 1 {
+		g.positions[key]--
+	} else {
+		delete(g.positions, key)
+	}
+
+	switch move.Type {
+	case PlaceMove:
+		piece, _ := g.Board.RemovePiece(move.To)
+		g.reserves[piece.Color][piece.Type]++
+	case MovementMove:
+		piece, _ := g.Board.RemovePiece(move.To)
+		g.Board.PlacePiece(move.From, piece)
+	}
+
+	g.Ply--
+	g.ToMove = g.ToMove.Opponent()
+	g.Result, g.ResultReason = InProgress, ""
+
+	return move, true
+}
+
+// History returns the moves played so far, oldest first
+func (g *GameState) History() []Move {
+	return g.history
+}
+
+// Clone returns an independent copy of the game, e.g. for an engine to search on
+// while the original stays on screen
+func (g *GameState) Clone() *GameState {
+	board := NewHexBoard()
+	for coord, stack := range g.Board.Pieces {
+		board.Pieces[coord] = append([]Piece{}, stack...)
+	}
+	board.zobrist = g.Board.zobrist
+
+	reserves := make(map[PieceColor]map[PieceType]int)
+	for color, counts := range g.reserves {
+		reserves[color] = make(map[PieceType]int)
+		for pieceType, count := range counts {
+			reserves[color][pieceType] = count
+		}
+	}
+
+	positions := make(map[uint64]int, len(g.positions))
+	for key, count := range g.positions {
+		positions[key] = count
+	}
+
+	clone := *g
+	clone.Board = board
+	clone.reserves = reserves
+	clone.history = append([]Move{}, g.history...)
+	clone.redo = append([]Move{}, g.redo...)
+	clone.positions = positions
+	return &clone
+}
+
+// StartPosition returns the position string the game was set up from, or "" for an empty board
+func (g *GameState) StartPosition() string {
+	return g.startPosition
+}
+
+// startState returns a fresh copy of the game as it was before its first move
+func (g *GameState) startState() *GameState {
+	if g.startPosition != "" {
+		if start, err := ParsePosition(g.startPosition); err == nil {
+			return start
+		}
+	}
+	return NewGameState(g.Rules)
+}
@@ -5,8 +5,10 @@ import (
 )
 
 // HexBoard manages the game board state
+// Pieces should only be changed through PlacePiece and RemovePiece, which keep the Zobrist key in step
 type HexBoard struct {
-	Pieces map[HexCoordinate][]Piece // Stack of pieces at each position (beetles can stack)
+	Pieces  map[HexCoordinate][]Piece // Stack of pieces at each position (beetles can stack)
+	zobrist uint64
 }
 
 // NewHexBoard creates a new empty board
@@ -22,6 +24,10 @@ func (b *HexBoard) PlacePiece(coord HexCoordinate, piece Piece) {
 		b.Pieces[coord] = []Piece{}
 	}
 	b.Pieces[coord] = append(b.Pieces[coord], piece)
+	b.zobrist ^= zobristPiece(piece, coord, len(b.Pieces[coord]))
+	if ZobristDebug {
+		b.checkZobrist()
+	}
 }
 
 // RemovePiece removes the top piece from the given coordinate
@@ -32,11 +38,15 @@ func (b *HexBoard) RemovePiece(coord HexCoordinate) (Piece, bool) {
 	}
 	
 	piece := stack[len(stack)-1]
+	b.zobrist ^= zobristPiece(piece, coord, len(stack))
 	b.Pieces[coord] = stack[:len(stack)-1]
 	
 	if len(b.Pieces[coord]) == 0 {
 		delete(b.Pieces, coord)
 	}
+	if ZobristDebug {
+		b.checkZobrist()
+	}
 	
 	return piece, true
 }
@@ -50,6 +60,33 @@ func (b *HexBoard) GetTopPiece(coord HexCoordinate) (Piece, bool) {
 	return stack[len(stack)-1], true
 }
 
+// Height returns the number of pieces stacked at the given coordinate
+func (b *HexBoard) Height(coord HexCoordinate) int {
+	return len(b.Pieces[coord])
+}
+
+// FindPiece returns the coordinate of a piece anywhere on the board, including inside stacks
+func (b *HexBoard) FindPiece(piece Piece) (HexCoordinate, bool) {
+	for coord, stack := range b.Pieces {
+		for _, p := range stack {
+			if p == piece {
+				return coord, true
+			}
+		}
+	}
+	return HexCoordinate{}, false
+}
+
+// IsSurrounded checks if all six neighbours of a coordinate are occupied
+func (b *HexBoard) IsSurrounded(coord HexCoordinate) bool {
+	for _, neighbor := range coord.Neighbors() {
+		if !b.IsOccupied(neighbor) {
+			return false
+		}
+	}
+	return true
+}
+
 // IsOccupied checks if a coordinate has any pieces
 func (b *HexBoard) IsOccupied(coord HexCoordinate) bool {
 	stack, exists := b.Pieces[coord]
@@ -8,6 +8,24 @@ type HexCoordinate struct {
 	R int // Row coordinate
 }
 
+// CubeCoordinate is the same position in cube coordinates, where X+Y+Z is always 0
+// Rotations and reflections are plain swaps and negations of the three axes
+type CubeCoordinate struct {
+	X int
+	Y int
+	Z int
+}
+
+// hexDirections are the steps to each neighbour, indexed like Neighbors
+var hexDirections = [6]HexCoordinate{
+	{Q: 1, R: 0},  // East
+	{Q: 1, R: -1}, // Northeast
+	{Q: 0, R: -1}, // Northwest
+	{Q: -1, R: 0}, // West
+	{Q: -1, R: 1}, // Southwest
+	{Q: 0, R: 1},  // Southeast
+}
+
 // NewHexCoordinate creates a new hexagonal coordinate
 func NewHexCoordinate(q, r int) HexCoordinate {
 	return HexCoordinate{Q: q, R: r}
@@ -18,6 +36,16 @@ func (h HexCoordinate) Equals(other HexCoordinate) bool {
 	return h.Q == other.Q && h.R == other.R
 }
 
+// Add returns the coordinate shifted by another one
+func (h HexCoordinate) Add(other HexCoordinate) HexCoordinate {
+	return HexCoordinate{Q: h.Q + other.Q, R: h.R + other.R}
+}
+
+// Subtract returns the offset from other to h
+func (h HexCoordinate) Subtract(other HexCoordinate) HexCoordinate {
+	return HexCoordinate{Q: h.Q - other.Q, R: h.R - other.R}
+}
+
 // Neighbors returns all 6 neighboring coordinates
 func (h HexCoordinate) Neighbors() []HexCoordinate {
 	return []HexCoordinate{
@@ -30,11 +58,102 @@ func (h HexCoordinate) Neighbors() []HexCoordinate {
 	}
 }
 
+// Neighbor returns the adjacent cell in a direction, numbered like Neighbors
+// Directions wrap around, so dir+1 and dir-1 are the directions either side of dir
+func (h HexCoordinate) Neighbor(dir int) HexCoordinate {
+	return h.Add(hexDirections[((dir%6)+6)%6])
+}
+
+// DirectionTo returns the direction of an adjacent cell, or -1 if it is not adjacent
+func (h HexCoordinate) DirectionTo(other HexCoordinate) int {
+	step := other.Subtract(h)
+	for dir, d := range hexDirections {
+		if step == d {
+			return dir
+		}
+	}
+	return -1
+}
+
 // Distance calculates the hex distance between two coordinates
 func (h HexCoordinate) Distance(other HexCoordinate) int {
 	return (abs(h.Q-other.Q) + abs(h.Q+h.R-other.Q-other.R) + abs(h.R-other.R)) / 2
 }
 
+// ToCube converts to cube coordinates
+func (h HexCoordinate) ToCube() CubeCoordinate {
+	return CubeCoordinate{X: h.Q, Y: -h.Q - h.R, Z: h.R}
+}
+
+// ToAxial converts back to axial coordinates
+func (c CubeCoordinate) ToAxial() HexCoordinate {
+	return HexCoordinate{Q: c.X, R: c.Z}
+}
+
+// RotateAround turns the coordinate about a centre by steps of 60°, clockwise as drawn
+// (east turns to southeast); negative steps turn anticlockwise
+func (h HexCoordinate) RotateAround(center HexCoordinate, steps int) HexCoordinate {
+	c := h.Subtract(center).ToCube()
+	for i := 0; i < ((steps%6)+6)%6; i++ {
+		c = CubeCoordinate{X: -c.Z, Y: -c.X, Z: -c.Y}
+	}
+	return c.ToAxial().Add(center)
+}
+
+// ReflectAcross mirrors the coordinate across the east-west line through a centre,
+// so northeast and southeast trade places
+func (h HexCoordinate) ReflectAcross(center HexCoordinate) HexCoordinate {
+	c := h.Subtract(center).ToCube()
+	return CubeCoordinate{X: -c.Y, Y: -c.X, Z: -c.Z}.ToAxial().Add(center)
+}
+
+// Ring returns the cells at exactly the given distance, going round from the southwest corner
+func (h HexCoordinate) Ring(radius int) []HexCoordinate {
+	if radius <= 0 {
+		return []HexCoordinate{h}
+	}
+
+	cells := make([]HexCoordinate, 0, 6*radius)
+	cell := h.Add(HexCoordinate{Q: hexDirections[4].Q * radius, R: hexDirections[4].R * radius})
+	for dir := 0; dir < 6; dir++ {
+		for i := 0; i < radius; i++ {
+			cells = append(cells, cell)
+			cell = cell.Neighbor(dir)
+		}
+	}
+	return cells
+}
+
+// Spiral returns the cell itself followed by each ring out to the given distance
+func (h HexCoordinate) Spiral(radius int) []HexCoordinate {
+	cells := []HexCoordinate{h}
+	for r := 1; r <= radius; r++ {
+		cells = append(cells, h.Ring(r)...)
+	}
+	return cells
+}
+
+// Traverse walks in a straight line from the coordinate and returns the first cell for
+// which keepGoing is false, e.g. the landing cell of a grasshopper jumping over pieces
+func (h HexCoordinate) Traverse(dir int, keepGoing func(HexCoordinate) bool) HexCoordinate {
+	cell := h.Neighbor(dir)
+	for keepGoing(cell) {
+		cell = cell.Neighbor(dir)
+	}
+	return cell
+}
+
+// ToOffset converts to "odd-r" offset coordinates, the column and row of a layout where
+// odd rows are pushed half a cell to the right, as the board is drawn in the terminal
+func (h HexCoordinate) ToOffset() (col, row int) {
+	return h.Q + (h.R-(h.R&1))/2, h.R
+}
+
+// FromOffset converts odd-r offset coordinates back to axial
+func FromOffset(col, row int) HexCoordinate {
+	return HexCoordinate{Q: col - (row-(row&1))/2, R: row}
+}
+
 func abs(x int) int {
 	if x < 0 {
 		return -x
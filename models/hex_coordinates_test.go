@@ -0,0 +1,198 @@
+package models
+
+import "testing"
+
+// testCenters are the centres the geometry tests turn and mirror about
+var testCenters = []HexCoordinate{{Q: 0, R: 0}, {Q: 2, R: -3}, {Q: -1, R: 4}}
+
+// testCells is every cell within a few steps of the origin
+var testCells = NewHexCoordinate(0, 0).Spiral(4)
+
+func TestSixRotationsGiveBackTheCell(t *testing.T) {
+	for _, center := range testCenters {
+		for _, cell := range testCells {
+			if got := cell.RotateAround(center, 6); got != cell {
+				t.Errorf("%v turned six times about %v gives %v", cell, center, got)
+			}
+			turned := cell
+			for i := 0; i < 6; i++ {
+				turned = turned.RotateAround(center, 1)
+			}
+			if turned != cell {
+				t.Errorf("%v turned one step six times about %v gives %v", cell, center, turned)
+			}
+			if got := cell.RotateAround(center, 2).RotateAround(center, -2); got != cell {
+				t.Errorf("%v turned back and forth about %v gives %v", cell, center, got)
+			}
+		}
+	}
+}
+
+func TestRotationAndReflectionPreserveDistance(t *testing.T) {
+	for _, center := range testCenters {
+		for _, a := range testCells {
+			for _, b := range testCells {
+				want := a.Distance(b)
+				for steps := 1; steps < 6; steps++ {
+					if got := a.RotateAround(center, steps).Distance(b.RotateAround(center, steps)); got != want {
+						t.Fatalf("distance from %v to %v is %d, turned %d steps about %v it is %d", a, b, want, steps, center, got)
+					}
+				}
+				if got := a.ReflectAcross(center).Distance(b.ReflectAcross(center)); got != want {
+					t.Fatalf("distance from %v to %v is %d, mirrored across %v it is %d", a, b, want, center, got)
+				}
+			}
+			if got := a.RotateAround(center, 1).Distance(center); got != a.Distance(center) {
+				t.Errorf("%v turned about %v moved to distance %d from it", a, center, got)
+			}
+		}
+	}
+}
+
+func TestReflectingTwiceGivesBackTheCell(t *testing.T) {
+	for _, center := range testCenters {
+		for _, cell := range testCells {
+			if got := cell.ReflectAcross(center).ReflectAcross(center); got != cell {
+				t.Errorf("%v mirrored twice across %v gives %v", cell, center, got)
+			}
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	for _, center := range testCenters {
+		for radius := 1; radius <= 5; radius++ {
+			ring := center.Ring(radius)
+			if len(ring) != 6*radius {
+				t.Errorf("ring %d about %v has %d cells, want %d", radius, center, len(ring), 6*radius)
+			}
+			seen := map[HexCoordinate]bool{}
+			for i, cell := range ring {
+				if d := cell.Distance(center); d != radius {
+					t.Errorf("ring %d about %v holds %v at distance %d", radius, center, cell, d)
+				}
+				if seen[cell] {
+					t.Errorf("ring %d about %v holds %v twice", radius, center, cell)
+				}
+				seen[cell] = true
+				// Each cell follows on from the one before, all the way round
+				if next := ring[(i+1)%len(ring)]; cell.Distance(next) != 1 {
+					t.Errorf("ring %d about %v jumps from %v to %v", radius, center, cell, next)
+				}
+			}
+		}
+	}
+}
+
+func TestSpiralCoversEachRingOnce(t *testing.T) {
+	for _, center := range testCenters {
+		const radius = 4
+		spiral := center.Spiral(radius)
+		if want := 1 + 3*radius*(radius+1); len(spiral) != want {
+			t.Errorf("spiral of radius %d about %v has %d cells, want %d", radius, center, len(spiral), want)
+		}
+		if spiral[0] != center {
+			t.Errorf("spiral about %v starts at %v", center, spiral[0])
+		}
+
+		seen := map[HexCoordinate]bool{}
+		perRing := make([]int, radius+1)
+		for _, cell := range spiral {
+			if seen[cell] {
+				t.Errorf("spiral about %v holds %v twice", center, cell)
+			}
+			seen[cell] = true
+			d := cell.Distance(center)
+			if d > radius {
+				t.Fatalf("spiral of radius %d about %v holds %v at distance %d", radius, center, cell, d)
+			}
+			perRing[d]++
+		}
+		for r := 1; r <= radius; r++ {
+			if perRing[r] != 6*r {
+				t.Errorf("spiral about %v holds %d cells of ring %d, want %d", center, perRing[r], r, 6*r)
+			}
+		}
+	}
+}
+
+func TestOffsetRoundTrip(t *testing.T) {
+	for _, cell := range NewHexCoordinate(1, -1).Spiral(6) {
+		col, row := cell.ToOffset()
+		if got := FromOffset(col, row); got != cell {
+			t.Errorf("%v to offset (%d, %d) and back gives %v", cell, col, row, got)
+		}
+	}
+}
+
+func TestCubeRoundTrip(t *testing.T) {
+	for _, cell := range testCells {
+		cube := cell.ToCube()
+		if cube.X+cube.Y+cube.Z != 0 {
+			t.Errorf("%v in cube coordinates is %v, which does not sum to 0", cell, cube)
+		}
+		if got := cube.ToAxial(); got != cell {
+			t.Errorf("%v to cube %v and back gives %v", cell, cube, got)
+		}
+	}
+}
+
+func TestNeighborAndDirectionTo(t *testing.T) {
+	for _, cell := range testCells {
+		neighbors := cell.Neighbors()
+		for dir := 0; dir < 6; dir++ {
+			next := cell.Neighbor(dir)
+			if next != neighbors[dir] {
+				t.Errorf("%v.Neighbor(%d) = %v, Neighbors lists %v", cell, dir, next, neighbors[dir])
+			}
+			if got := cell.DirectionTo(next); got != dir {
+				t.Errorf("%v.DirectionTo(%v) = %d, want %d", cell, next, got, dir)
+			}
+			// Directions wrap around both ways
+			if got := cell.Neighbor(dir - 6); got != next {
+				t.Errorf("%v.Neighbor(%d) = %v, want %v", cell, dir-6, got, next)
+			}
+			if got := cell.Neighbor(dir + 6); got != next {
+				t.Errorf("%v.Neighbor(%d) = %v, want %v", cell, dir+6, got, next)
+			}
+			// The opposite direction leads back
+			if back := next.Neighbor(dir + 3); back != cell {
+				t.Errorf("%v stepping %d then %d gives %v", cell, dir, dir+3, back)
+			}
+		}
+		if got := cell.DirectionTo(cell); got != -1 {
+			t.Errorf("%v.DirectionTo itself = %d, want -1", cell, got)
+		}
+		if far := cell.Add(NewHexCoordinate(2, 0)); cell.DirectionTo(far) != -1 {
+			t.Errorf("%v.DirectionTo(%v) = %d, want -1", cell, far, cell.DirectionTo(far))
+		}
+	}
+}
+
+func TestTraverse(t *testing.T) {
+	occupied := map[HexCoordinate]bool{{Q: 1, R: 0}: true, {Q: 2, R: 0}: true, {Q: 3, R: 0}: true}
+	start := NewHexCoordinate(0, 0)
+
+	// A grasshopper jumping east clears the line and lands on the first empty cell
+	if got := start.Traverse(0, func(c HexCoordinate) bool { return occupied[c] }); got != NewHexCoordinate(4, 0) {
+		t.Errorf("traversing east over the line lands on %v, want (4, 0)", got)
+	}
+	// With nothing in the way it stops on the neighbour
+	for dir := 1; dir < 6; dir++ {
+		if got := start.Traverse(dir, func(c HexCoordinate) bool { return occupied[c] }); got != start.Neighbor(dir) {
+			t.Errorf("traversing %d with nothing in the way lands on %v, want %v", dir, got, start.Neighbor(dir))
+		}
+	}
+	// Every cell visited lies on the straight line, one step further each time
+	steps := 0
+	end := start.Traverse(4, func(c HexCoordinate) bool {
+		steps++
+		if want := NewHexCoordinate(-steps, steps); c != want {
+			t.Errorf("step %d of the traversal southwest is %v, want %v", steps, c, want)
+		}
+		return steps < 5
+	})
+	if want := NewHexCoordinate(-5, 5); end != want {
+		t.Errorf("traversal southwest ends at %v, want %v", end, want)
+	}
+}
@@ -32,50 +32,73 @@ func (r *HexRenderer) Render(width, height int) []string {
 	lines := []string{}
 	
 	// Get board bounds with some padding
-	minQ, maxQ, minR, maxR := r.board.GetBounds()
-	minQ -= 1
-	maxQ += 1
-	minR -= 1
-	maxR += 1
+	minCol, maxCol, minR, maxR := r.layoutBounds()
 	
 	// Header
 	lines = append(lines, fmt.Sprintf("  Pieces on board: %d", r.board.PieceCount()))
 	lines = append(lines, "")
 	
 	// Render each row
+	pinned := r.board.PinnedPieces()
 	for rowIdx := minR; rowIdx <= maxR; rowIdx++ {
-		line := r.renderRow(rowIdx, minQ, maxQ)
+		line := r.renderRow(rowIdx, minCol, maxCol, pinned)
 		lines = append(lines, line)
 	}
 	
 	lines = append(lines, "")
 	lines = append(lines, "  Coordinates: (q, r)")
+	if len(pinned) > 0 {
+		lines = append(lines, "  {..} pinned: moving it would split the hive")
+	}
 	
 	return lines
 }
 
+// layoutBounds returns the odd-r offset columns and the rows covering the board, with
+// one cell of padding all round; drawing by offset column keeps neighbours next to each other
+func (r *HexRenderer) layoutBounds() (minCol, maxCol, minR, maxR int) {
+	first := true
+	for coord := range r.board.Pieces {
+		col, row := coord.ToOffset()
+		if first || col < minCol {
+			minCol = col
+		}
+		if first || col > maxCol {
+			maxCol = col
+		}
+		if first || row < minR {
+			minR = row
+		}
+		if first || row > maxR {
+			maxR = row
+		}
+		first = false
+	}
+	return minCol - 1, maxCol + 1, minR - 1, maxR + 1
+}
+
 // renderRow renders a single row of hexagons
-func (r *HexRenderer) renderRow(row, minQ, maxQ int) string {
+func (r *HexRenderer) renderRow(row, minCol, maxCol int, pinned map[HexCoordinate]bool) string {
 	var sb strings.Builder
 	
 	// Calculate offset for this row (for hex staggering)
 	offset := ""
-	if row%2 != 0 {
+	if row&1 != 0 {
 		offset = "  " // Offset odd rows
 	}
 	
 	sb.WriteString(offset)
 	sb.WriteString("  ") // Left padding
 	
-	for q := minQ; q <= maxQ; q++ {
-		coord := HexCoordinate{Q: q, R: row}
+	for col := minCol; col <= maxCol; col++ {
+		coord := FromOffset(col, row)
 		
 		if piece, exists := r.board.GetTopPiece(coord); exists {
 			// Draw piece with border
-			sb.WriteString(fmt.Sprintf("[%s]", piece.ShortString()))
+			sb.WriteString(pieceLabel(piece, pinned[coord]))
 		} else {
 			// Empty space - show coordinate
-			sb.WriteString(fmt.Sprintf(" %2d,%-2d ", q, row))
+			sb.WriteString(fmt.Sprintf(" %2d,%-2d ", coord.Q, coord.R))
 		}
 		
 		sb.WriteString(" ") // Spacing between hexes
@@ -99,26 +122,23 @@ func (r *HexRenderer) RenderCompact(width, height int) []string {
 	lines = append(lines, fmt.Sprintf("  %d pieces", r.board.PieceCount()))
 	lines = append(lines, "")
 	
-	minQ, maxQ, minR, maxR := r.board.GetBounds()
-	minQ -= 1
-	maxQ += 1
-	minR -= 1
-	maxR += 1
+	minCol, maxCol, minR, maxR := r.layoutBounds()
 	
+	pinned := r.board.PinnedPieces()
 	for rowIdx := minR; rowIdx <= maxR; rowIdx++ {
 		var sb strings.Builder
 		
 		// Offset for hex grid
-		if rowIdx%2 != 0 {
+		if rowIdx&1 != 0 {
 			sb.WriteString(" ")
 		}
 		sb.WriteString(" ")
 		
-		for q := minQ; q <= maxQ; q++ {
-			coord := HexCoordinate{Q: q, R: rowIdx}
+		for col := minCol; col <= maxCol; col++ {
+			coord := FromOffset(col, rowIdx)
 			
 			if piece, exists := r.board.GetTopPiece(coord); exists {
-				sb.WriteString(fmt.Sprintf("[%s]", piece.ShortString()))
+				sb.WriteString(pieceLabel(piece, pinned[coord]))
 			} else {
 				sb.WriteString("  . ")
 			}
@@ -131,47 +151,52 @@ func (r *HexRenderer) RenderCompact(width, height int) []string {
 	return lines
 }
 
-// RenderWithHighlight renders the board with a specific coordinate highlighted
-func (r *HexRenderer) RenderWithHighlight(highlight HexCoordinate) []string {
+// RenderWithHighlight renders the board with the given coordinates highlighted,
+// e.g. where a piece moved from and to
+func (r *HexRenderer) RenderWithHighlight(highlights ...HexCoordinate) []string {
 	if r.board.PieceCount() == 0 {
 		return r.Render(0, 0)
 	}
 	
+	marked := make(map[HexCoordinate]bool)
+	labels := []string{}
+	for _, highlight := range highlights {
+		marked[highlight] = true
+		labels = append(labels, fmt.Sprintf("%d,%d", highlight.Q, highlight.R))
+	}
+	
 	lines := []string{}
-	lines = append(lines, fmt.Sprintf("  %d pieces (highlighting %d,%d)", 
-		r.board.PieceCount(), highlight.Q, highlight.R))
+	lines = append(lines, fmt.Sprintf("  %d pieces (highlighting %s)", 
+		r.board.PieceCount(), strings.Join(labels, " → ")))
 	lines = append(lines, "")
 	
-	minQ, maxQ, minR, maxR := r.board.GetBounds()
-	minQ -= 1
-	maxQ += 1
-	minR -= 1
-	maxR += 1
+	minCol, maxCol, minR, maxR := r.layoutBounds()
 	
+	pinned := r.board.PinnedPieces()
 	for rowIdx := minR; rowIdx <= maxR; rowIdx++ {
 		var sb strings.Builder
 		
-		if rowIdx%2 != 0 {
+		if rowIdx&1 != 0 {
 			sb.WriteString("  ")
 		}
 		sb.WriteString("  ")
 		
-		for q := minQ; q <= maxQ; q++ {
-			coord := HexCoordinate{Q: q, R: rowIdx}
+		for col := minCol; col <= maxCol; col++ {
+			coord := FromOffset(col, rowIdx)
 			
-			if coord.Equals(highlight) {
+			if marked[coord] {
 				sb.WriteString(">>")
 			}
 			
 			if piece, exists := r.board.GetTopPiece(coord); exists {
-				sb.WriteString(fmt.Sprintf("[%s]", piece.ShortString()))
+				sb.WriteString(pieceLabel(piece, pinned[coord]))
 			} else {
-				sb.WriteString(fmt.Sprintf(" %2d,%-2d ", q, rowIdx))
+				sb.WriteString(fmt.Sprintf(" %2d,%-2d ", coord.Q, coord.R))
 			}
 			
-			if coord.Equals(highlight) {
+			if marked[coord] {
 				sb.WriteString("<<")
-			} else {
+			} else if !marked[FromOffset(col+1, rowIdx)] {
 				sb.WriteString("  ")
 			}
 		}
@@ -182,3 +207,10 @@ func (r *HexRenderer) RenderWithHighlight(highlight HexCoordinate) []string {
 	return lines
 }
 
+// pieceLabel formats a piece for the board, using braces instead of brackets for pinned pieces
+func pieceLabel(piece Piece, pinned bool) string {
+	if pinned {
+		return fmt.Sprintf("{%s}", piece.ShortString())
+	}
+	return fmt.Sprintf("[%s]", piece.ShortString())
+}
@@ -0,0 +1,73 @@
+package models
+
+// ArticulationPoints returns the occupied cells whose removal would split the hive in two
+// Cells are treated as a graph where each occupied cell links to its occupied neighbours
+func (b *HexBoard) ArticulationPoints() map[HexCoordinate]bool {
+	points := make(map[HexCoordinate]bool)
+	if len(b.Pieces) < 3 {
+		return points
+	}
+
+	discovery := make(map[HexCoordinate]int)
+	low := make(map[HexCoordinate]int)
+	timer := 0
+
+	// Tarjan's depth-first search: a cell is an articulation point when one of its
+	// children cannot reach above it without passing through it
+	var visit func(cell, parent HexCoordinate, isRoot bool)
+	visit = func(cell, parent HexCoordinate, isRoot bool) {
+		timer++
+		discovery[cell] = timer
+		low[cell] = timer
+		children := 0
+
+		for _, next := range cell.Neighbors() {
+			if !b.IsOccupied(next) {
+				continue
+			}
+			if _, seen := discovery[next]; !seen {
+				children++
+				visit(next, cell, false)
+				if low[next] < low[cell] {
+					low[cell] = low[next]
+				}
+				if !isRoot && low[next] >= discovery[cell] {
+					points[cell] = true
+				}
+			} else if !next.Equals(parent) && discovery[next] < low[cell] {
+				low[cell] = discovery[next]
+			}
+		}
+
+		if isRoot && children > 1 {
+			points[cell] = true
+		}
+	}
+
+	for start := range b.Pieces {
+		visit(start, start, true)
+		break
+	}
+
+	return points
+}
+
+// IsPinned reports whether lifting the top piece at coord would split the hive
+// A piece on top of a stack never pins anything, since the cell below stays occupied
+func (b *HexBoard) IsPinned(coord HexCoordinate) bool {
+	if len(b.Pieces[coord]) != 1 {
+		return false
+	}
+	return b.ArticulationPoints()[coord]
+}
+
+// PinnedPieces returns the coordinates of every piece that cannot be lifted without splitting the hive
+func (b *HexBoard) PinnedPieces() map[HexCoordinate]bool {
+	pinned := make(map[HexCoordinate]bool)
+	for coord := range b.ArticulationPoints() {
+		if len(b.Pieces[coord]) == 1 {
+			pinned[coord] = true
+		}
+	}
+	return pinned
+}
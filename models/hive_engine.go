@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Engine chooses moves for a computer player
+// Engines search a copy of the game, so the state passed in is left as it was
+type Engine interface {
+	Name() string
+	BestMove(g *GameState, limits SearchLimits) Move
+}
+
+// SearchLimits bounds how long an engine may think about a move
+type SearchLimits struct {
+	Depth int           // Deepest search in plies, 0 for no limit
+	Time  time.Duration // Time budget, 0 for no limit
+}
+
+// DefaultSearchLimits is how long an engine thinks when nothing else is asked for
+var DefaultSearchLimits = SearchLimits{Time: 2 * time.Second}
+
+// Scores are in hundredths of a piece, from the point of view of the side to move
+const (
+	WinScore      = 1000000
+	winScoreBound = WinScore - 1000 // Scores beyond this are forced wins, nearer wins higher
+)
+
+// pieceValues weighs each insect by how much it can do once it is free to move,
+// so that searches try moves of the stronger pieces first
+var pieceValues = map[PieceType]int{
+	QueenBee:    0,
+	Ant:         60,
+	Beetle:      45,
+	Grasshopper: 30,
+	Spider:      25,
+	Mosquito:    50,
+	Ladybug:     40,
+	Pillbug:     35,
+}
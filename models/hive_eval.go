@@ -0,0 +1,260 @@
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// An evaluation weights file sets the weight of each term, one per line; terms counted per
+// insect name the piece type as well. Terms left out keep their default weight:
+//
+//	# Crowd the opponent's Queen, keep ants free
+//	queen_neighbors -150
+//	mobility A 3
+//	reserve B 20
+//
+// Blank lines and lines starting with # are ignored.
+
+// Names of the evaluation terms, as used in weights files and breakdowns
+const (
+	TermQueenNeighbors = "queen_neighbors"
+	TermMobility       = "mobility"
+	TermPinned         = "pinned"
+	TermBeetleOnQueen  = "beetle_on_queen"
+	TermReserve        = "reserve"
+)
+
+// EvalWeights scores a position as a weighted sum of features counted for each player
+// Every feature is counted for both players and the difference taken, so a weight is what
+// one more of the feature is worth to its owner, negative for a drawback
+type EvalWeights struct {
+	QueenNeighbors int               // Per occupied cell around the player's own Queen
+	Mobility       map[PieceType]int // Per cell a piece of each type can move to
+	Pinned         int               // Per piece the player cannot move without splitting the hive
+	BeetleOnQueen  int               // Per piece of the player's on top of the opponent's Queen
+	Reserve        map[PieceType]int // Per piece of each type still in hand
+}
+
+// DefaultEvalWeights are the weights the engines use unless others are loaded
+// Surrounding the opponent's Queen is what wins, so it weighs heaviest; ants and beetles
+// are worth most when free to roam
+var DefaultEvalWeights = EvalWeights{
+	QueenNeighbors: -120,
+	Mobility: map[PieceType]int{
+		QueenBee:    6,
+		Ant:         2,
+		Beetle:      8,
+		Grasshopper: 5,
+		Spider:      5,
+		Mosquito:    3,
+		Ladybug:     4,
+		Pillbug:     6,
+	},
+	Pinned:        -15,
+	BeetleOnQueen: 80,
+	Reserve: map[PieceType]int{
+		Ant:         20,
+		Beetle:      15,
+		Grasshopper: 10,
+		Spider:      8,
+		Mosquito:    17,
+		Ladybug:     13,
+		Pillbug:     12,
+	},
+}
+
+// EvalTerm is the weighted score of one term for each player
+type EvalTerm struct {
+	Name  string
+	White int
+	Black int
+}
+
+// Score is the term's contribution from White's point of view
+func (t EvalTerm) Score() int {
+	return t.White - t.Black
+}
+
+// Evaluate scores a position for the side to move, using the default weights
+func Evaluate(g *GameState) int {
+	return DefaultEvalWeights.Evaluate(g)
+}
+
+// Evaluate scores a position for the side to move: a win is WinScore, a loss -WinScore,
+// and a game in progress the sum of its terms
+func (w EvalWeights) Evaluate(g *GameState) int {
+	switch g.Result {
+	case Draw:
+		return 0
+	case WhiteWins, BlackWins:
+		if g.Result == winFor(g.ToMove) {
+			return WinScore
+		}
+		return -WinScore
+	}
+
+	score := 0
+	for _, term := range w.Terms(g) {
+		score += term.Score()
+	}
+	if g.ToMove == Black {
+		return -score
+	}
+	return score
+}
+
+// Terms breaks the score of a position down by term, in a fixed order
+func (w EvalWeights) Terms(g *GameState) []EvalTerm {
+	terms := []EvalTerm{
+		{Name: TermQueenNeighbors},
+		{Name: TermMobility},
+		{Name: TermPinned},
+		{Name: TermBeetleOnQueen},
+		{Name: TermReserve},
+	}
+	queenNeighbors, mobility, pinnedPieces, beetleOnQueen, reserve := &terms[0], &terms[1], &terms[2], &terms[3], &terms[4]
+	add := func(term *EvalTerm, color PieceColor, score int) {
+		if color == White {
+			term.White += score
+		} else {
+			term.Black += score
+		}
+	}
+
+	// The hive is walked once for the terms that depend on what lies where
+	pinned := g.Board.PinnedPieces()
+	for _, coord := range g.Board.GetAllCoordinates() {
+		stack := g.Board.Pieces[coord]
+		top := stack[len(stack)-1]
+
+		if top.Type == QueenBee {
+			for _, neighbor := range coord.Neighbors() {
+				if g.Board.IsOccupied(neighbor) {
+					add(queenNeighbors, top.Color, w.QueenNeighbors)
+				}
+			}
+		}
+		for _, below := range stack[:len(stack)-1] {
+			if below.Type == QueenBee && below.Color != top.Color {
+				add(beetleOnQueen, top.Color, w.BeetleOnQueen)
+			}
+			// The Queen of a stack is covered, but the cells around her still count
+			if below.Type == QueenBee {
+				for _, neighbor := range coord.Neighbors() {
+					if g.Board.IsOccupied(neighbor) {
+						add(queenNeighbors, below.Color, w.QueenNeighbors)
+					}
+				}
+			}
+		}
+
+		if pinned[coord] {
+			add(pinnedPieces, top.Color, w.Pinned)
+		} else if weight := w.Mobility[top.Type]; weight != 0 && g.QueenPlaced(top.Color) {
+			add(mobility, top.Color, weight*len(g.Board.liftedDestinations(coord)))
+		}
+	}
+
+	for _, color := range []PieceColor{White, Black} {
+		for _, info := range g.Rules.PieceTypes() {
+			add(reserve, color, g.Remaining(color, info.Symbol)*w.Reserve[info.Symbol])
+		}
+	}
+	return terms
+}
+
+// ReadEvalWeights reads a weights file over the default weights, reporting errors with
+// their line number
+func ReadEvalWeights(r io.Reader) (EvalWeights, error) {
+	weights := EvalWeights{
+		QueenNeighbors: DefaultEvalWeights.QueenNeighbors,
+		Mobility:       make(map[PieceType]int),
+		Pinned:         DefaultEvalWeights.Pinned,
+		BeetleOnQueen:  DefaultEvalWeights.BeetleOnQueen,
+		Reserve:        make(map[PieceType]int),
+	}
+	for pieceType, weight := range DefaultEvalWeights.Mobility {
+		weights.Mobility[pieceType] = weight
+	}
+	for pieceType, weight := range DefaultEvalWeights.Reserve {
+		weights.Reserve[pieceType] = weight
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := weights.set(strings.Fields(line)); err != nil {
+			return weights, fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return weights, err
+	}
+	return weights, nil
+}
+
+// LoadEvalWeights reads a weights file from disk
+func LoadEvalWeights(path string) (EvalWeights, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return EvalWeights{}, err
+	}
+	defer file.Close()
+
+	weights, err := ReadEvalWeights(file)
+	if err != nil {
+		return weights, fmt.Errorf("%s: %w", path, err)
+	}
+	return weights, nil
+}
+
+// set applies one line of a weights file: a term, a piece type for per-insect terms, and a weight
+func (w *EvalWeights) set(fields []string) error {
+	weight, err := strconv.Atoi(fields[len(fields)-1])
+	if len(fields) < 2 || err != nil {
+		return fmt.Errorf("expected a term and a whole number weight, e.g. %s -120", TermQueenNeighbors)
+	}
+
+	name := strings.ToLower(fields[0])
+	switch name {
+	case TermQueenNeighbors, TermPinned, TermBeetleOnQueen:
+		if len(fields) != 2 {
+			return fmt.Errorf("%s takes a weight only", name)
+		}
+	case TermMobility, TermReserve:
+		if len(fields) != 3 {
+			return fmt.Errorf("%s takes a piece type and a weight, e.g. %s A 2", name, name)
+		}
+	default:
+		return fmt.Errorf("unknown term %q", fields[0])
+	}
+
+	switch name {
+	case TermQueenNeighbors:
+		w.QueenNeighbors = weight
+	case TermPinned:
+		w.Pinned = weight
+	case TermBeetleOnQueen:
+		w.BeetleOnQueen = weight
+	default:
+		pieceType := PieceType(strings.ToUpper(fields[1]))
+		if _, known := GetPieceInfo(pieceType); !known {
+			return fmt.Errorf("unknown piece type %q", fields[1])
+		}
+		if name == TermMobility {
+			w.Mobility[pieceType] = weight
+		} else {
+			w.Reserve[pieceType] = weight
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"io"
+)
+
+// MatchMoveLimit draws engine games that go on this many turns, as engines can shuffle forever
+const MatchMoveLimit = 150
+
+// MatchResult tallies an engine-versus-engine match from the first engine's point of view
+type MatchResult struct {
+	Wins   int
+	Losses int
+	Draws  int
+}
+
+// PlayEngineGame plays one game between two engines and returns the finished game
+func PlayEngineGame(white, black Engine, rules RuleSet, limits SearchLimits) *GameState {
+	g := NewGameState(rules)
+	g.MoveLimit = MatchMoveLimit
+	for !g.IsOver() {
+		engine := white
+		if g.ToMove == Black {
+			engine = black
+		}
+		if _, err := g.Play(engine.BestMove(g, limits)); err != nil {
+			// An engine that picks an illegal move forfeits
+			g.Result = winFor(g.ToMove.Opponent())
+			g.ResultReason = fmt.Sprintf("%s played an illegal move: %s", engine.Name(), err)
+		}
+	}
+	return g
+}
+
+// RunMatch plays a match of the given number of games, the engines taking White in turn,
+// and writes each result and the final score to out
+func RunMatch(out io.Writer, first, second Engine, games int, rules RuleSet, limits SearchLimits) MatchResult {
+	var result MatchResult
+	for i := 0; i < games; i++ {
+		white, black := first, second
+		if i%2 == 1 {
+			white, black = second, first
+		}
+
+		g := PlayEngineGame(white, black, rules, limits)
+		firstColor := White
+		if white != first {
+			firstColor = Black
+		}
+		switch g.Result {
+		case Draw:
+			result.Draws++
+		case winFor(firstColor):
+			result.Wins++
+		default:
+			result.Losses++
+		}
+		fmt.Fprintf(out, "Game %d: %s (White) vs %s (Black): %s, %s after %d turns\n",
+			i+1, white.Name(), black.Name(), g.Result, g.ResultReason, g.Turn())
+	}
+
+	fmt.Fprintf(out, "%s vs %s: +%d -%d =%d\n", first.Name(), second.Name(), result.Wins, result.Losses, result.Draws)
+	return result
+}
@@ -0,0 +1,281 @@
+package models
+
+// MoveDestinations returns every cell the top piece at from can move to
+// The piece is lifted off the board while its moves are generated and put back afterwards
+func (b *HexBoard) MoveDestinations(from HexCoordinate) []HexCoordinate {
+	// One-Hive rule: a piece holding the hive together cannot move at all
+	if b.IsPinned(from) {
+		return nil
+	}
+	return b.liftedDestinations(from)
+}
+
+// liftedDestinations generates the moves of the top piece at from, which the caller already
+// knows is free to leave without splitting the hive
+func (b *HexBoard) liftedDestinations(from HexCoordinate) []HexCoordinate {
+	piece, ok := b.RemovePiece(from)
+	if !ok {
+		return nil
+	}
+	defer b.PlacePiece(from, piece)
+
+	return b.destinationsAs(piece.Type, from)
+}
+
+// destinationsAs generates the moves of a lifted piece as if it were of the given type
+func (b *HexBoard) destinationsAs(pieceType PieceType, from HexCoordinate) []HexCoordinate {
+	switch pieceType {
+	case QueenBee, Pillbug:
+		return b.slideTargets(from)
+	case Beetle:
+		return b.beetleTargets(from)
+	case Grasshopper:
+		return b.grasshopperTargets(from)
+	case Spider:
+		return b.spiderTargets(from)
+	case Ant:
+		return b.antTargets(from)
+	case Ladybug:
+		return b.ladybugTargets(from)
+	case Mosquito:
+		return b.mosquitoTargets(from)
+	}
+	return nil
+}
+
+// CanMoveTo reports whether the top piece at from can move to the given coordinate
+func (b *HexBoard) CanMoveTo(from, to HexCoordinate) bool {
+	for _, dest := range b.MoveDestinations(from) {
+		if dest.Equals(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSlide reports whether a piece can step from one cell to an adjacent one
+// The moving piece must already be lifted off from, so it travels at level Height(from)
+// and lands at level Height(to). The step is blocked by a gate when both shared
+// neighbours stand higher than that path, and a step at ground level must keep
+// touching the hive through one of them. Beetles climbing use the same rule.
+func (b *HexBoard) CanSlide(from, to HexCoordinate) bool {
+	dir := from.DirectionTo(to)
+	if dir < 0 {
+		return false
+	}
+
+	left, right := sharedNeighbors(from, dir)
+	level := b.Height(from)
+	if b.Height(to) > level {
+		level = b.Height(to)
+	}
+
+	// Freedom to move: the gap between the shared neighbours is too narrow
+	if b.Height(left) > level && b.Height(right) > level {
+		return false
+	}
+
+	// A ground-level slide must not lose contact with the hive
+	if level == 0 && !b.IsOccupied(left) && !b.IsOccupied(right) {
+		return false
+	}
+
+	return true
+}
+
+// sharedNeighbors returns the two cells adjacent to both coord and its neighbour in direction dir
+// Directions form a ring, so they are the directions on either side of dir
+func sharedNeighbors(coord HexCoordinate, dir int) (HexCoordinate, HexCoordinate) {
+	return coord.Neighbor(dir + 1), coord.Neighbor(dir - 1)
+}
+
+// slideTargets returns the empty neighbours reachable by a single ground-level slide
+func (b *HexBoard) slideTargets(from HexCoordinate) []HexCoordinate {
+	targets := []HexCoordinate{}
+	for _, to := range from.Neighbors() {
+		if !b.IsOccupied(to) && b.CanSlide(from, to) {
+			targets = append(targets, to)
+		}
+	}
+	return targets
+}
+
+// beetleTargets returns the cells a beetle can reach: one step, climbing on or off the hive
+func (b *HexBoard) beetleTargets(from HexCoordinate) []HexCoordinate {
+	targets := []HexCoordinate{}
+	for _, to := range from.Neighbors() {
+		if b.CanSlide(from, to) {
+			targets = append(targets, to)
+		}
+	}
+	return targets
+}
+
+// grasshopperTargets returns the first empty cell in each direction past at least one piece
+func (b *HexBoard) grasshopperTargets(from HexCoordinate) []HexCoordinate {
+	targets := []HexCoordinate{}
+	for dir := range hexDirections {
+		if !b.IsOccupied(from.Neighbor(dir)) {
+			continue
+		}
+		targets = append(targets, from.Traverse(dir, b.IsOccupied))
+	}
+	return targets
+}
+
+// spiderTargets returns the cells reached by exactly three slides without revisiting a cell
+func (b *HexBoard) spiderTargets(from HexCoordinate) []HexCoordinate {
+	seen := make(map[HexCoordinate]bool)
+	targets := []HexCoordinate{}
+
+	var walk func(path []HexCoordinate)
+	walk = func(path []HexCoordinate) {
+		current := path[len(path)-1]
+		if len(path) == 4 {
+			if !seen[current] {
+				seen[current] = true
+				targets = append(targets, current)
+			}
+			return
+		}
+		for _, next := range b.slideTargets(current) {
+			if containsCoordinate(path, next) {
+				continue
+			}
+			walk(append(path, next))
+		}
+	}
+	walk([]HexCoordinate{from})
+
+	return targets
+}
+
+// antTargets returns every cell reachable by any number of slides around the hive
+func (b *HexBoard) antTargets(from HexCoordinate) []HexCoordinate {
+	visited := map[HexCoordinate]bool{from: true}
+	queue := []HexCoordinate{from}
+	targets := []HexCoordinate{}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range b.slideTargets(current) {
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			targets = append(targets, next)
+			queue = append(queue, next)
+		}
+	}
+
+	return targets
+}
+
+// ladybugTargets returns the cells reached by two steps on top of the hive and one step down
+func (b *HexBoard) ladybugTargets(from HexCoordinate) []HexCoordinate {
+	targets := []HexCoordinate{}
+	for _, first := range from.Neighbors() {
+		if !b.IsOccupied(first) || !b.CanSlide(from, first) {
+			continue
+		}
+		for _, second := range first.Neighbors() {
+			if second.Equals(from) || !b.IsOccupied(second) || !b.CanSlide(first, second) {
+				continue
+			}
+			for _, to := range second.Neighbors() {
+				if to.Equals(from) || b.IsOccupied(to) || containsCoordinate(targets, to) {
+					continue
+				}
+				if b.CanSlide(second, to) {
+					targets = append(targets, to)
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// mosquitoTargets returns the moves of every insect the mosquito touches
+// On top of the hive it moves as a beetle, and touching only other mosquitoes it cannot move
+func (b *HexBoard) mosquitoTargets(from HexCoordinate) []HexCoordinate {
+	if b.IsOccupied(from) {
+		return b.beetleTargets(from)
+	}
+
+	copied := make(map[PieceType]bool)
+	targets := []HexCoordinate{}
+	for _, neighbor := range from.Neighbors() {
+		top, occupied := b.GetTopPiece(neighbor)
+		if !occupied || top.Type == Mosquito || copied[top.Type] {
+			continue
+		}
+		copied[top.Type] = true
+		for _, to := range b.destinationsAs(top.Type, from) {
+			if !containsCoordinate(targets, to) {
+				targets = append(targets, to)
+			}
+		}
+	}
+	return targets
+}
+
+// PillbugThrows returns where the pillbug (or a mosquito copying it) at coord can move the
+// adjacent piece at target: up onto itself and down into an empty neighbouring cell
+// Stacked or pinned pieces cannot be moved, and each step obeys the height gate rule
+func (b *HexBoard) PillbugThrows(coord, target HexCoordinate) []HexCoordinate {
+	if b.Height(target) != 1 || b.IsPinned(target) || coord.DirectionTo(target) < 0 {
+		return nil
+	}
+
+	piece, _ := b.RemovePiece(target)
+	defer b.PlacePiece(target, piece)
+
+	if !b.CanSlide(target, coord) {
+		return nil
+	}
+
+	targets := []HexCoordinate{}
+	for _, to := range coord.Neighbors() {
+		if to.Equals(target) || b.IsOccupied(to) {
+			continue
+		}
+		if b.CanSlide(coord, to) {
+			targets = append(targets, to)
+		}
+	}
+	return targets
+}
+
+// CanThrow reports whether the top piece at coord can use the pillbug's special ability:
+// a pillbug, or a mosquito on the ground touching a pillbug
+func (b *HexBoard) CanThrow(coord HexCoordinate) bool {
+	top, occupied := b.GetTopPiece(coord)
+	if !occupied {
+		return false
+	}
+	switch top.Type {
+	case Pillbug:
+		return true
+	case Mosquito:
+		if b.Height(coord) != 1 {
+			return false
+		}
+		for _, neighbor := range coord.Neighbors() {
+			if n, ok := b.GetTopPiece(neighbor); ok && n.Type == Pillbug {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsCoordinate checks whether coords includes the given coordinate
+func containsCoordinate(coords []HexCoordinate, coord HexCoordinate) bool {
+	for _, c := range coords {
+		if c.Equals(coord) {
+			return true
+		}
+	}
+	return false
+}
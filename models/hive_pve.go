@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// engineMoveMsg carries the move the computer chose back to the interface
+type engineMoveMsg struct {
+	search int // Search the move answers, so a move for an undone position is dropped
+	move   Move
+}
+
+// WithComputer makes the computer play one colour at the given level, the human typing
+// moves for the other
+func (m HiveModel) WithComputer(level Difficulty, color PieceColor) HiveModel {
+	m.difficulty = level
+	m.engine = m.newEngine()
+	m.computer = color
+	m.spinner = spinner.New(spinner.WithSpinner(spinner.Dot))
+
+	name := fmt.Sprintf("Computer (%s)", level)
+	if color == White {
+		m.whiteName, m.blackName = name, "Player 1"
+	} else {
+		m.whiteName, m.blackName = "Player 1", name
+	}
+	return m
+}
+
+// Init starts the computer thinking straight away when it has the first move
+func (m HiveModel) Init() tea.Cmd {
+	_, think := m.maybeThink()
+	return tea.Batch(textinput.Blink, think)
+}
+
+// newEngine creates an engine at the chosen level, judging positions with the loaded weights
+func (m HiveModel) newEngine() Engine {
+	engine := m.difficulty.NewEngine()
+	engine.Weights = m.weights
+	return engine
+}
+
+// maybeThink starts a search when it is the computer's turn
+// The search runs on a copy of the game as a tea.Cmd, so the interface keeps drawing.
+// Every search gets an engine of its own, so one that was abandoned and is still winding
+// down never shares anything with the next
+func (m HiveModel) maybeThink() (HiveModel, tea.Cmd) {
+	if m.engine == nil || m.thinking || m.replay != nil || m.state.IsOver() || m.state.ToMove != m.computer {
+		return m, nil
+	}
+
+	m.thinking = true
+	m.search++
+	m.engine = m.newEngine()
+	search, engine, state, limits := m.search, m.engine, m.state.Clone(), m.difficulty.Limits()
+	think := func() tea.Msg {
+		return engineMoveMsg{search: search, move: engine.BestMove(state, limits)}
+	}
+	return m, tea.Batch(m.spinner.Tick, think)
+}
+
+// stopThinking abandons the current search and tells the engine to stop; the move it
+// still returns is ignored when it arrives
+func (m HiveModel) stopThinking() HiveModel {
+	if engine, ok := m.engine.(interface{ Stop() }); ok && m.thinking {
+		engine.Stop()
+	}
+	m.thinking = false
+	m.search++
+	return m
+}
+
+// handleEngineMove plays the computer's move, then lets it move again if the human must pass
+func (m HiveModel) handleEngineMove(msg engineMoveMsg) (HiveModel, tea.Cmd) {
+	if !m.thinking || msg.search != m.search {
+		return m, nil
+	}
+	m.thinking = false
+
+	notation := m.state.MoveString(msg.move)
+	if _, err := m.state.Play(msg.move); err != nil {
+		m.lastError = fmt.Sprintf("Computer move %s rejected: %s", notation, err)
+		return m, nil
+	}
+	m.messages = append(m.messages, fmt.Sprintf("(computer) %s", notation))
+	return m.autoPass().maybeThink()
+}
@@ -0,0 +1,208 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// replaySpeeds are the autoplay delays between moves, slowest first
+var replaySpeeds = []time.Duration{2 * time.Second, time.Second, 500 * time.Millisecond, 250 * time.Millisecond}
+
+// replayTickMsg advances an autoplaying replay by one move
+type replayTickMsg struct {
+	run int // Autoplay run the tick belongs to, so ticks from a stopped run are ignored
+}
+
+// hiveReplay steps through a finished or loaded game without changing it
+type hiveReplay struct {
+	state    *GameState // The game wound to the position being shown
+	moves    []Move
+	notation []string // Each move in standard notation
+	index    int      // Number of moves played on state
+	autoplay bool
+	speed    int // Index into replaySpeeds
+	run      int
+}
+
+// newHiveReplay sets up a replay of a game, starting from its first position
+func newHiveReplay(game *GameState) *hiveReplay {
+	state := game.startState()
+	state.MoveLimit = game.MoveLimit
+	return &hiveReplay{
+		state:    state,
+		moves:    append([]Move{}, game.History()...),
+		notation: game.MoveStrings(),
+		speed:    1,
+	}
+}
+
+// forward plays the next move, reporting false at the end of the game
+func (r *hiveReplay) forward() bool {
+	if r.index >= len(r.moves) {
+		return false
+	}
+	r.state.apply(r.moves[r.index])
+	r.index++
+	return true
+}
+
+// back takes the last shown move back, reporting false at the start of the game
+func (r *hiveReplay) back() bool {
+	if r.index == 0 {
+		return false
+	}
+	r.state.undo()
+	r.index--
+	return true
+}
+
+// tick schedules the next autoplay step
+func (r *hiveReplay) tick() tea.Cmd {
+	run := r.run
+	return tea.Tick(replaySpeeds[r.speed], func(time.Time) tea.Msg {
+		return replayTickMsg{run: run}
+	})
+}
+
+// highlights returns the cells touched by the last shown move: where it came from and went to
+func (r *hiveReplay) highlights() []HexCoordinate {
+	if r.index == 0 {
+		return nil
+	}
+	move := r.moves[r.index-1]
+	switch move.Type {
+	case PlaceMove:
+		return []HexCoordinate{move.To}
+	case MovementMove:
+		return []HexCoordinate{move.From, move.To}
+	}
+	return nil
+}
+
+// startReplay switches the interface to replaying the current game
+func (m HiveModel) startReplay() HiveModel {
+	if len(m.state.History()) == 0 {
+		m.lastError = "Nothing to replay: no moves played yet"
+		return m
+	}
+	m.replay = newHiveReplay(m.state)
+	m.textInput.Blur()
+	return m
+}
+
+// stopReplay returns to the game as it was before the replay
+func (m HiveModel) stopReplay() HiveModel {
+	m.replay = nil
+	m.textInput.Focus()
+	return m
+}
+
+// handleReplayKey steps through the replay: arrows move one turn, home/end jump,
+// space starts or stops autoplay and +/- change its speed
+func (m HiveModel) handleReplayKey(msg tea.KeyMsg) (HiveModel, tea.Cmd) {
+	r := m.replay
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		return m.stopReplay().maybeThink()
+	case "right", "l":
+		r.autoplay = false
+		r.forward()
+	case "left", "h":
+		r.autoplay = false
+		r.back()
+	case "home", "g":
+		r.autoplay = false
+		for r.back() {
+		}
+	case "end", "G":
+		r.autoplay = false
+		for r.forward() {
+		}
+	case " ", "p":
+		r.autoplay = !r.autoplay
+		if r.autoplay {
+			if r.index == len(r.moves) {
+				for r.back() {
+				}
+			}
+			r.run++
+			return m, r.tick()
+		}
+	case "+", "=", "up":
+		if r.speed < len(replaySpeeds)-1 {
+			r.speed++
+		}
+	case "-", "down":
+		if r.speed > 0 {
+			r.speed--
+		}
+	}
+	return m, nil
+}
+
+// advanceReplay plays the next move of an autoplaying replay, stopping at the end
+func (m HiveModel) advanceReplay(msg replayTickMsg) (HiveModel, tea.Cmd) {
+	r := m.replay
+	if !r.autoplay || msg.run != r.run {
+		return m, nil
+	}
+	if !r.forward() || r.index == len(r.moves) {
+		r.autoplay = false
+		return m, nil
+	}
+	return m, r.tick()
+}
+
+// renderReplayPanel shows the replay position with the last move highlighted
+func (m HiveModel) renderReplayPanel(width, height int) string {
+	r := m.replay
+	var b strings.Builder
+
+	b.WriteString(PanelTitleStyle.Render(fmt.Sprintf("Replay: move %d of %d", r.index, len(r.moves))))
+	b.WriteString("\n")
+
+	if r.index > 0 {
+		b.WriteString(PieceStyle.Render(fmt.Sprintf("Last move: %s", r.notation[r.index-1])))
+	} else {
+		b.WriteString(PieceStyle.Render("Start of the game"))
+	}
+	b.WriteString("\n")
+	if r.state.IsOver() {
+		b.WriteString(ResultBannerStyle.Render(fmt.Sprintf("%s: %s", r.state.Result, r.state.ResultReason)))
+		b.WriteString("\n")
+	}
+
+	renderer := NewHexRenderer(r.state.Board)
+	for _, line := range renderer.RenderWithHighlight(r.highlights()...) {
+		b.WriteString(BoardStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	content := b.String()
+	return PanelStyle.Width(width).Height(height).Render(content)
+}
+
+// renderReplayControls replaces the command input while a replay is shown
+func (m HiveModel) renderReplayControls(width, height int) string {
+	r := m.replay
+	var b strings.Builder
+
+	b.WriteString(PanelTitleStyle.Render("Replay Controls"))
+	b.WriteString("\n\n")
+
+	status := "paused"
+	if r.autoplay {
+		status = "playing"
+	}
+	b.WriteString(PieceStyle.Render(fmt.Sprintf("Autoplay %s, one move every %s", status, replaySpeeds[r.speed])))
+	b.WriteString("\n\n")
+	b.WriteString(HelpStyle.Render("←/→: step • home/end: jump • space: autoplay • +/-: speed • esc: back to game"))
+
+	content := b.String()
+	return PanelStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+)
+
+// ValidatePlacement checks whether a piece may be placed from the reserve at the given coordinate
+// Returns nil when the placement is legal, otherwise an error describing the broken rule
+func ValidatePlacement(board *HexBoard, piece Piece, coord HexCoordinate) error {
+	// Pieces are never placed on top of the hive, only beetles climb
+	if top, occupied := board.GetTopPiece(coord); occupied {
+		return fmt.Errorf("(%d,%d) is already occupied by %s", coord.Q, coord.R, top)
+	}
+
+	switch board.PieceCount() {
+	case 0:
+		// The first piece can go anywhere
+		return nil
+	case 1:
+		// The second piece must touch the first one, whatever its colour
+		for _, neighbor := range coord.Neighbors() {
+			if board.IsOccupied(neighbor) {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be placed next to the first piece")
+	}
+
+	// Every later placement touches only friendly pieces (judged by the top of each stack)
+	touchesFriend := false
+	for _, neighbor := range coord.Neighbors() {
+		top, occupied := board.GetTopPiece(neighbor)
+		if !occupied {
+			continue
+		}
+		if top.Color != piece.Color {
+			return fmt.Errorf("touches an opponent piece at (%d,%d)", neighbor.Q, neighbor.R)
+		}
+		touchesFriend = true
+	}
+
+	if !touchesFriend {
+		return fmt.Errorf("must touch at least one of your own pieces")
+	}
+
+	return nil
+}
+
+// ValidateMovement checks whether piece may move from one coordinate to another
+// Returns nil when the move is legal, otherwise an error describing the broken rule
+func ValidateMovement(board *HexBoard, piece Piece, from, to HexCoordinate) error {
+	top, occupied := board.GetTopPiece(from)
+	if !occupied {
+		return fmt.Errorf("no piece at (%d,%d)", from.Q, from.R)
+	}
+	if top != piece {
+		return fmt.Errorf("the piece on top of (%d,%d) is %s, not %s", from.Q, from.R, top, piece)
+	}
+	if from.Equals(to) {
+		return fmt.Errorf("%s must move to a different cell", piece)
+	}
+
+	if board.IsPinned(from) {
+		return fmt.Errorf("lifting %s would split the hive", piece)
+	}
+
+	if !board.CanMoveTo(from, to) {
+		info, _ := GetPieceInfo(piece.Type)
+		return fmt.Errorf("%s cannot reach (%d,%d): a %s %s", piece, to.Q, to.R, info.Name, info.Movement)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setupOption is a single toggle on the Hive setup screen
+type setupOption struct {
+	label       string
+	description string
+	enabled     func(m *HiveSetupModel) *bool
+}
+
+// HiveSetupModel lets the players choose the rules before a Hive game starts
+type HiveSetupModel struct {
+	options        []setupOption
+	rules          RuleSet
+	versusComputer bool
+	difficulty     Difficulty
+	cursor         int
+	started        bool
+}
+
+// NewHiveSetupModel creates a setup screen for the base game, expansions and options switched off
+func NewHiveSetupModel() HiveSetupModel {
+	return HiveSetupModel{
+		options: []setupOption{
+			{
+				label:       "Mosquito",
+				description: "Copies the movement of the insects it touches",
+				enabled:     func(m *HiveSetupModel) *bool { return &m.rules.Mosquito },
+			},
+			{
+				label:       "Ladybug",
+				description: "Two steps on top of the hive, then one down",
+				enabled:     func(m *HiveSetupModel) *bool { return &m.rules.Ladybug },
+			},
+			{
+				label:       "Pillbug",
+				description: "Slides one cell or moves an adjacent piece over itself",
+				enabled:     func(m *HiveSetupModel) *bool { return &m.rules.Pillbug },
+			},
+			{
+				label:       "Tournament Opening",
+				description: "Neither player may place the Queen on their first turn",
+				enabled:     func(m *HiveSetupModel) *bool { return &m.rules.TournamentOpening },
+			},
+			{
+				label:       "Play against the computer",
+				description: "You play White, the computer answers as Black",
+				enabled:     func(m *HiveSetupModel) *bool { return &m.versusComputer },
+			},
+		},
+		rules:      BaseRules,
+		difficulty: Medium,
+		cursor:     0,
+	}
+}
+
+func (m HiveSetupModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m HiveSetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			// After the options come the computer's level, then the start button
+			if m.cursor < len(m.options)+1 {
+				m.cursor++
+			}
+		case "left", "h":
+			if m.cursor == len(m.options) && m.difficulty > Beginner {
+				m.difficulty--
+			}
+		case "right", "l":
+			if m.cursor == len(m.options) && m.difficulty < Expert {
+				m.difficulty++
+			}
+		case " ", "x":
+			m = m.toggle()
+		case "enter":
+			if m.cursor <= len(m.options) {
+				return m.toggle(), nil
+			}
+			m.started = true
+			return m, tea.Quit
+		case "s":
+			m.started = true
+			return m, tea.Quit
+		case "ctrl+c", "q", "esc":
+			m.started = false
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// toggle flips the option under the cursor, or moves the computer's level up one, wrapping around
+func (m HiveSetupModel) toggle() HiveSetupModel {
+	switch {
+	case m.cursor < len(m.options):
+		enabled := m.options[m.cursor].enabled(&m)
+		*enabled = !*enabled
+	case m.cursor == len(m.options):
+		m.difficulty = (m.difficulty + 1) % Difficulty(len(Difficulties))
+	}
+	return m
+}
+
+func (m HiveSetupModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(TitleStyle.Render("🐝  HIVE SETUP  🐝"))
+	b.WriteString("\n\n")
+
+	b.WriteString(InputLabelStyle.Render("  Expansion pieces, rules and opponent"))
+	b.WriteString("\n\n")
+
+	for i, option := range m.options {
+		cursor := "   "
+		check := "[ ]"
+		if *option.enabled(&m) {
+			check = "[x]"
+		}
+
+		line := ItemStyle.Render(fmt.Sprintf("%s %s", check, option.label))
+		if m.cursor == i {
+			cursor = " ▶ "
+			line = SelectedItemStyle.Render(fmt.Sprintf("%s %s", check, option.label))
+		}
+
+		b.WriteString(cursor + line)
+		b.WriteString("\n")
+
+		if m.cursor == i {
+			b.WriteString(DescriptionStyle.Render(fmt.Sprintf("     %s", option.description)))
+			b.WriteString("\n")
+		}
+	}
+
+	level := fmt.Sprintf("Computer level: ◀ %s ▶", m.difficulty)
+	cursor := "   "
+	if m.cursor == len(m.options) {
+		cursor = " ▶ "
+		b.WriteString(cursor + SelectedItemStyle.Render(level))
+		b.WriteString("\n")
+		b.WriteString(DescriptionStyle.Render(fmt.Sprintf("     %s", m.difficulty.Description())))
+	} else {
+		b.WriteString(cursor + ItemStyle.Render(level))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("\n")
+	start := ItemStyle.Render(fmt.Sprintf("Start game (%s)", m.rules.Summary()))
+	cursor = "   "
+	if m.cursor == len(m.options)+1 {
+		cursor = " ▶ "
+		start = SelectedItemStyle.Render(fmt.Sprintf("Start game (%s)", m.rules.Summary()))
+	}
+	b.WriteString(cursor + start)
+	b.WriteString("\n")
+
+	b.WriteString(HelpStyle.Render("  ↑/↓: navigate  •  space/enter: toggle  •  ←/→: level  •  s: start  •  q/esc: back"))
+
+	return BorderStyle.Render(b.String())
+}
+
+// Getters
+func (m HiveSetupModel) Started() bool {
+	return m.started
+}
+
+func (m HiveSetupModel) Rules() RuleSet {
+	return m.rules
+}
+
+func (m HiveSetupModel) VersusComputer() bool {
+	return m.versusComputer
+}
+
+func (m HiveSetupModel) Difficulty() Difficulty {
+	return m.difficulty
+}
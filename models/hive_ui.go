@@ -2,9 +2,12 @@ package models
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -31,6 +34,12 @@ var (
 	ErrorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF0000")).
 			PaddingLeft(2)
+	
+	ResultBannerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1a1a1a")).
+			Background(lipgloss.Color("#FFD700")).
+			Bold(true).
+			Padding(0, 2)
 
 )
 
@@ -39,42 +48,56 @@ type HiveModel struct {
 	game         Game
 	textInput    textinput.Model
 	messages     []string
+	whiteName    string
+	blackName    string
+	state        *GameState
 	board        *HexBoard
 	renderer     *HexRenderer
 	width        int
 	height       int
 	lastError    string
+	replay       *hiveReplay // Set while stepping through a replay of the game
+	
+	// Computer opponent, when playing against the computer
+	engine       Engine
+	computer     PieceColor
+	thinking     bool
+	search       int // Counts searches started, to recognise the reply to the latest one
+	spinner      spinner.Model
+	difficulty   Difficulty
+	weights      EvalWeights // Evaluation shown by the eval command and used by the computer
 }
 
 // NewHiveModel creates a new Hive game model with 4-panel layout
-func NewHiveModel(game Game) HiveModel {
+// The rule set chosen during setup decides which expansion pieces are in play
+func NewHiveModel(game Game, rules RuleSet) HiveModel {
 	ti := textinput.New()
-	ti.Placeholder = "place WQ 0 0  |  move WQ 0 0 1 0"
+	ti.Placeholder = "place WQ 0 0  |  move WQ 0 0 1 0  |  wA1 wQ-"
 	ti.Focus()
 	ti.CharLimit = 100
 	ti.Width = 40
 	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF06B7"))
 	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
 	
-	board := NewHexBoard()
-	renderer := NewHexRenderer(board)
+	state := NewGameState(rules)
+	renderer := NewHexRenderer(state.Board)
 	
 	return HiveModel{
 		game:      game,
 		textInput: ti,
 		messages:  []string{},
-		board:     board,
+		whiteName: "Player 1",
+		blackName: "Player 2",
+		state:     state,
+		board:     state.Board,
 		renderer:  renderer,
 		lastError: "",
 		width:     120,
 		height:    30,
+		weights:   DefaultEvalWeights,
 	}
 }
 
-func (m HiveModel) Init() tea.Cmd {
-	return textinput.Blink
-}
-
 func (m HiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	
@@ -84,17 +107,39 @@ func (m HiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 		
+	case engineMoveMsg:
+		return m.handleEngineMove(msg)
+		
+	case spinner.TickMsg:
+		if m.thinking {
+			m.spinner, cmd = m.spinner.Update(msg)
+		}
+		return m, cmd
+		
+	case replayTickMsg:
+		if m.replay != nil {
+			return m.advanceReplay(msg)
+		}
+		return m, nil
+		
 	case tea.KeyMsg:
+		if m.replay != nil {
+			return m.handleReplayKey(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			return m, tea.Quit
+		case "ctrl+z":
+			return m.handleCommand("undo")
+		case "ctrl+y":
+			return m.handleCommand("redo")
 		case "enter":
 			value := strings.TrimSpace(m.textInput.Value())
 			if value != "" {
-				m = m.handleCommand(value)
+				m, cmd = m.handleCommand(value)
 				m.textInput.SetValue("")
 			}
-			return m, nil
+			return m, cmd
 		}
 	}
 	
@@ -102,26 +147,233 @@ func (m HiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m HiveModel) handleCommand(input string) HiveModel {
+// gameOverCommands are the commands handleCommand still accepts once the game is decided
+const gameOverCommands = "new | undo | redo | save/load <file> | position | setposition | replay | eval | quit"
+
+func (m HiveModel) handleCommand(input string) (HiveModel, tea.Cmd) {
 	// Add to message history
 	m.messages = append(m.messages, input)
 	m.lastError = ""
 	
-	// Parse command
-	command := ParseCommand(input)
+	// Parse command, resolving any Hive notation against the board
+	command := ParseCommandOnBoard(input, m.board)
 	
 	if command.Type == InvalidCommand {
 		m.lastError = command.Error
-		return m
+		return m, nil
+	}
+	
+	// Once the game is decided only gameOverCommands make sense: start again, go back, or look at and keep the game
+	if m.state.IsOver() {
+		switch command.Type {
+		case NewGameCommand, UndoCommand, RedoCommand, QuitCommand, SaveCommand, LoadCommand, PositionCommand, SetPositionCommand, ReplayCommand, EvalCommand:
+		default:
+			m.lastError = "The game is over: type " + gameOverCommands
+			return m, nil
+		}
+	}
+	
+	// While the computer thinks the board is its own; commands that replace the position call it off
+	if m.thinking {
+		switch command.Type {
+		case PlaceCommand, MoveCommand, PassCommand, RedoCommand, PerftCommand:
+			m.lastError = "The computer is thinking"
+			return m, nil
+		case NewGameCommand, UndoCommand, LoadCommand, SetPositionCommand, ReplayCommand:
+			m = m.stopThinking()
+		}
 	}
 	
 	switch command.Type {
 	case PlaceCommand:
-		m = m.handlePlaceCommand(command)
+		m = m.handlePlaceCommand(command).autoPass()
 	case MoveCommand:
-		m = m.handleMoveCommand(command)
+		m = m.handleMoveCommand(command).autoPass()
+	case PassCommand:
+		if _, err := m.state.Play(Move{Type: PassMove}); err != nil {
+			m.lastError = fmt.Sprintf("Cannot pass: %s", err)
+		}
+	case NewGameCommand:
+		m = m.newGame()
+	case UndoCommand:
+		m = m.undoTurn()
+	case RedoCommand:
+		m = m.redoTurn()
+	case LimitCommand:
+		m.state.MoveLimit = command.Value
+	case PerftCommand:
+		start := time.Now()
+		nodes := m.state.Perft(command.Value)
+		m.messages = append(m.messages, fmt.Sprintf("(perft %d: %d nodes in %s)", command.Value, nodes, time.Since(start).Round(time.Millisecond)))
+	case SaveCommand:
+		record := NewGameRecord(m.state, m.whiteName, m.blackName, time.Now())
+		if m.engine != nil {
+			record.Headers["Difficulty"] = m.difficulty.String()
+		}
+		if err := SaveGameRecord(command.Argument, record); err != nil {
+			m.lastError = fmt.Sprintf("Cannot save: %s", err)
+		} else {
+			m.messages = append(m.messages, fmt.Sprintf("(saved %d moves to %s)", len(record.Moves), command.Argument))
+		}
+	case LoadCommand:
+		m = m.loadGame(command.Argument)
+	case PositionCommand:
+		m.messages = append(m.messages, fmt.Sprintf("(position %s)", m.state.PositionString()))
+	case SetPositionCommand:
+		m = m.setPosition(command.Argument)
+	case ReplayCommand:
+		if command.Argument != "" {
+			m = m.loadGame(command.Argument)
+			if m.lastError != "" {
+				return m, nil
+			}
+		}
+		m = m.startReplay()
+	case EvalCommand:
+		if command.Argument != "" {
+			m = m.loadWeights(command.Argument)
+			if m.lastError != "" {
+				return m, nil
+			}
+		}
+		m = m.showEvaluation()
+	case QuitCommand:
+		return m, tea.Quit
+	}
+	
+	return m.maybeThink()
+}
+
+// autoPass passes for every player left without a legal move, noting it in the history
+func (m HiveModel) autoPass() HiveModel {
+	for !m.state.IsOver() && !m.state.HasLegalMove() {
+		color := m.state.ToMove
+		if _, err := m.state.Play(Move{Type: PassMove}); err != nil {
+			break
+		}
+		m.messages = append(m.messages, fmt.Sprintf("(%s has no legal move and passes)", color.Name()))
+	}
+	return m
+}
+
+// undoTurn takes back the last move together with the passes that followed it, so the
+// player who made it is to move again
+func (m HiveModel) undoTurn() HiveModel {
+	move, ok := m.state.Undo()
+	if !ok {
+		m.lastError = "Nothing to undo"
+		return m
+	}
+	for move.Type == PassMove {
+		if move, ok = m.state.Undo(); !ok {
+			break
+		}
+	}
+	
+	// Against the computer, its reply goes too, so the human is back to move
+	if m.engine != nil && m.state.ToMove == m.computer && len(m.state.History()) > 0 {
+		return m.undoTurn()
+	}
+	return m
+}
+
+// redoTurn plays again what undoTurn took back: a move and the passes after it
+func (m HiveModel) redoTurn() HiveModel {
+	if _, ok := m.state.Redo(); !ok {
+		m.lastError = "Nothing to redo"
+		return m
+	}
+	for next, ok := m.state.NextRedo(); ok && next.Type == PassMove; next, ok = m.state.NextRedo() {
+		m.state.Redo()
+	}
+	
+	// Against the computer, its reply is replayed as well when there is one to redo
+	if _, ok := m.state.NextRedo(); ok && m.engine != nil && m.state.ToMove == m.computer {
+		return m.redoTurn()
+	}
+	return m
+}
+
+// loadGame replaces the current game with one replayed from a record file
+// Files ending in .sgf are imported as Boardspace.net games
+func (m HiveModel) loadGame(path string) HiveModel {
+	load := LoadGameRecord
+	if strings.EqualFold(filepath.Ext(path), ".sgf") {
+		load = LoadBoardspaceSGF
+	}
+	record, state, err := load(path)
+	if err != nil {
+		m.lastError = fmt.Sprintf("Cannot load: %s", err)
+		return m
 	}
 	
+	m.state = state
+	m.board = state.Board
+	m.renderer = NewHexRenderer(state.Board)
+	m.messages = append([]string{}, record.Moves...)
+	m.messages = append(m.messages, fmt.Sprintf("(loaded %s)", path))
+	// A game saved against the computer carries on at the level it was played at
+	if level, err := ParseDifficulty(record.Headers["Difficulty"]); err == nil && m.engine != nil {
+		m = m.WithComputer(level, m.computer)
+	}
+	if name, ok := record.Headers["White"]; ok {
+		m.whiteName = name
+	}
+	if name, ok := record.Headers["Black"]; ok {
+		m.blackName = name
+	}
+	return m
+}
+
+// loadWeights reads evaluation weights from a file, for the eval command and the computer alike
+// The computer's next search is handed the new weights; one already running keeps its own
+func (m HiveModel) loadWeights(path string) HiveModel {
+	weights, err := LoadEvalWeights(path)
+	if err != nil {
+		m.lastError = fmt.Sprintf("Cannot load weights: %s", err)
+		return m
+	}
+	m.weights = weights
+	m.messages = append(m.messages, fmt.Sprintf("(loaded evaluation weights from %s)", path))
+	return m
+}
+
+// showEvaluation lists what each evaluation term makes of the position, for both players
+func (m HiveModel) showEvaluation() HiveModel {
+	total := 0
+	for _, term := range m.weights.Terms(m.state) {
+		m.messages = append(m.messages, fmt.Sprintf("(eval %s: White %d, Black %d)", term.Name, term.White, term.Black))
+		total += term.Score()
+	}
+	m.messages = append(m.messages, fmt.Sprintf("(eval total: %+d for White)", total))
+	return m
+}
+
+// setPosition replaces the current game with one starting from a position string
+func (m HiveModel) setPosition(position string) HiveModel {
+	state, err := ParsePosition(position)
+	if err != nil {
+		m.lastError = fmt.Sprintf("Cannot set position: %s", err)
+		return m
+	}
+	state.MoveLimit = m.state.MoveLimit
+	
+	m.state = state
+	m.board = state.Board
+	m.renderer = NewHexRenderer(state.Board)
+	m.messages = []string{fmt.Sprintf("(set up turn %d, %s to move)", state.Turn(), state.ToMove.Name())}
+	return m
+}
+
+// newGame starts over with an empty board, keeping the rule set and move limit
+func (m HiveModel) newGame() HiveModel {
+	state := NewGameState(m.state.Rules)
+	state.MoveLimit = m.state.MoveLimit
+	
+	m.state = state
+	m.board = state.Board
+	m.renderer = NewHexRenderer(state.Board)
+	m.messages = []string{}
 	return m
 }
 
@@ -133,22 +385,29 @@ func (m HiveModel) handlePlaceCommand(cmd Command) HiveModel {
 		return m
 	}
 	
-	// Place the piece on the board
-	m.board.PlacePiece(cmd.ToCoord, piece)
+	// The game state checks turn order, reserves and placement rules before touching the board
+	if _, err := m.state.Play(Move{Type: PlaceMove, Piece: piece, To: cmd.ToCoord}); err != nil {
+		m.lastError = fmt.Sprintf("Cannot place %s: %s", piece, err)
+		return m
+	}
 	
 	return m
 }
 
 func (m HiveModel) handleMoveCommand(cmd Command) HiveModel {
-	// Remove piece from source
-	piece, ok := m.board.RemovePiece(cmd.FromCoord)
-	if !ok {
-		m.lastError = fmt.Sprintf("No piece at (%d, %d)", cmd.FromCoord.Q, cmd.FromCoord.R)
+	// Parse the piece string
+	piece, err := ParsePieceString(cmd.Piece)
+	if err != nil {
+		m.lastError = err.Error()
 		return m
 	}
 	
-	// Place piece at destination
-	m.board.PlacePiece(cmd.ToCoord, piece)
+	// The game state checks turn order and movement rules before touching the board
+	move := Move{Type: MovementMove, Piece: piece, From: cmd.FromCoord, To: cmd.ToCoord}
+	if _, err := m.state.Play(move); err != nil {
+		m.lastError = fmt.Sprintf("Cannot move %s: %s", piece, err)
+		return m
+	}
 	
 	return m
 }
@@ -163,6 +422,9 @@ func (m HiveModel) View() string {
 	// Top section: Left panel (pieces) and Right panel (board)
 	leftPanel := m.renderPiecesPanel(leftPanelWidth, topHeight)
 	rightPanel := m.renderBoardPanel(rightPanelWidth, topHeight)
+	if m.replay != nil {
+		rightPanel = m.renderReplayPanel(rightPanelWidth, topHeight)
+	}
 	
 	topSection := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
 	
@@ -171,6 +433,9 @@ func (m HiveModel) View() string {
 	historyWidth := (m.width / 2) - 2
 	
 	commandPanel := m.renderCommandPanel(commandWidth, bottomHeight)
+	if m.replay != nil {
+		commandPanel = m.renderReplayControls(commandWidth, bottomHeight)
+	}
 	historyPanel := m.renderHistoryPanel(historyWidth, bottomHeight)
 	
 	bottomSection := lipgloss.JoinHorizontal(lipgloss.Top, commandPanel, historyPanel)
@@ -183,28 +448,37 @@ func (m HiveModel) View() string {
 
 func (m HiveModel) renderPiecesPanel(width, height int) string {
 	var b strings.Builder
+	state := m.shownState()
 	
 	b.WriteString(PanelTitleStyle.Render("Piece Reference"))
 	b.WriteString("\n\n")
 	
+	b.WriteString(PieceStyle.Render("Rules: " + state.Rules.Summary()))
+	b.WriteString("\n\n")
+	
 	b.WriteString(PieceStyle.Render("White pieces:"))
 	b.WriteString("\n")
-	b.WriteString(PieceStyle.Render("  WQ - Queen"))
-	b.WriteString("\n")
-	b.WriteString(PieceStyle.Render("  WA1,2,3 - Ants"))
-	b.WriteString("\n")
-	b.WriteString(PieceStyle.Render("  WG1,2,3 - Hoppers"))
-	b.WriteString("\n")
-	b.WriteString(PieceStyle.Render("  WS1,2 - Spiders"))
+	for _, info := range state.Rules.PieceTypes() {
+		b.WriteString(PieceStyle.Render("  " + pieceReference(info)))
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
-	b.WriteString(PieceStyle.Render("  WB1,2 - Beetles"))
-	b.WriteString("\n\n")
 	
 	b.WriteString(PieceStyle.Render("Black pieces:"))
 	b.WriteString("\n")
 	b.WriteString(PieceStyle.Render("  BQ, BA1-3, etc."))
 	b.WriteString("\n\n")
 	
+	b.WriteString(PanelTitleStyle.Render(fmt.Sprintf("Turn %d: %s", state.Turn(), state.ToMove.Name())))
+	b.WriteString("\n")
+	for _, color := range []PieceColor{White, Black} {
+		b.WriteString(PieceStyle.Render(fmt.Sprintf("%s reserve:", color.Name())))
+		b.WriteString("\n")
+		b.WriteString(PieceStyle.Render("  " + m.reserveSummary(color)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	
 	b.WriteString(DescriptionStyle.Render("Coordinates:"))
 	b.WriteString("\n")
 	b.WriteString(DescriptionStyle.Render("  (q, r) format"))
@@ -215,12 +489,49 @@ func (m HiveModel) renderPiecesPanel(width, height int) string {
 	return PanelStyle.Width(width).Height(height).Render(content)
 }
 
+// pieceReference describes the white pieces of a type, e.g. "WA1-3 - Ant"
+func pieceReference(info PieceInfo) string {
+	if info.Quantity == 1 {
+		return fmt.Sprintf("W%s - %s", info.Symbol, info.Name)
+	}
+	return fmt.Sprintf("W%s1-%d - %s", info.Symbol, info.Quantity, info.Name)
+}
+
+// reserveSummary lists how many of each piece type a player still holds, e.g. "Q1 A3 G2 S2 B2"
+func (m HiveModel) reserveSummary(color PieceColor) string {
+	parts := []string{}
+	state := m.shownState()
+	for _, info := range state.Rules.PieceTypes() {
+		parts = append(parts, fmt.Sprintf("%s%d", info.Symbol, state.Remaining(color, info.Symbol)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shownState returns the position on screen: the replay's while one is running
+func (m HiveModel) shownState() *GameState {
+	if m.replay != nil {
+		return m.replay.state
+	}
+	return m.state
+}
+
 func (m HiveModel) renderBoardPanel(width, height int) string {
 	var b strings.Builder
 	
-	b.WriteString(PanelTitleStyle.Render("Game Board"))
+	title := "Game Board"
+	if m.engine != nil {
+		title = fmt.Sprintf("Game Board · vs Computer (%s)", m.difficulty)
+	}
+	b.WriteString(PanelTitleStyle.Render(title))
 	b.WriteString("\n")
 	
+	// Announce the result above the final position
+	if m.state.IsOver() {
+		banner := fmt.Sprintf("%s: %s", m.state.Result, m.state.ResultReason)
+		b.WriteString(ResultBannerStyle.Render(banner))
+		b.WriteString("\n")
+	}
+	
 	// Render the hexagonal board
 	boardLines := m.renderer.Render(width, height)
 	for _, line := range boardLines {
@@ -242,6 +553,11 @@ func (m HiveModel) renderCommandPanel(width, height int) string {
 	b.WriteString(m.textInput.View())
 	b.WriteString("\n")
 	
+	if m.thinking {
+		b.WriteString("\n")
+		b.WriteString(MessageStyle.Render(fmt.Sprintf("%s Computer is thinking…", m.spinner.View())))
+	}
+	
 	// Show error if present
 	if m.lastError != "" {
 		b.WriteString("\n")
@@ -249,7 +565,11 @@ func (m HiveModel) renderCommandPanel(width, height int) string {
 	}
 	
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("enter: submit • esc: quit"))
+	if m.state.IsOver() {
+		b.WriteString(HelpStyle.Render("game over • " + gameOverCommands))
+	} else {
+		b.WriteString(HelpStyle.Render("enter: submit • ctrl+z/ctrl+y: undo/redo • save/load <file> • position • esc: quit"))
+	}
 	
 	content := b.String()
 	return PanelStyle.Width(width).Height(height).Render(content)
@@ -269,6 +589,8 @@ func (m HiveModel) renderHistoryPanel(width, height int) string {
 		b.WriteString(DescriptionStyle.Render("  place WQ 0 0"))
 		b.WriteString("\n")
 		b.WriteString(DescriptionStyle.Render("  place BA1 1 0"))
+		b.WriteString("\n")
+		b.WriteString(DescriptionStyle.Render("  wA1 wQ-  (notation)"))
 	} else {
 		// Show last 5 messages
 		start := 0
@@ -0,0 +1,254 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// DefaultExploration is the UCT exploration constant, the textbook √2
+const DefaultExploration = math.Sqrt2
+
+// PlayoutPolicy picks the next move of a random playout
+type PlayoutPolicy func(g *GameState, moves []Move, rng *rand.Rand) Move
+
+// RandomPlayout picks any legal move with equal chance
+func RandomPlayout(g *GameState, moves []Move, rng *rand.Rand) Move {
+	return moves[rng.Intn(len(moves))]
+}
+
+// QueenPressurePlayout mostly picks moves that land next to the opponent's Queen, which is how
+// games are won, and otherwise plays at random; pure random play rarely surrounds anything
+func QueenPressurePlayout(g *GameState, moves []Move, rng *rand.Rand) Move {
+	if queen, found := g.Board.FindPiece(NewPiece(QueenBee, g.ToMove.Opponent(), 0)); found && rng.Intn(4) != 0 {
+		attacking := []Move{}
+		for _, move := range moves {
+			if move.To.Distance(queen) == 1 && (move.Type == PlaceMove || move.From.Distance(queen) > 1) {
+				attacking = append(attacking, move)
+			}
+		}
+		if len(attacking) > 0 {
+			return attacking[rng.Intn(len(attacking))]
+		}
+	}
+	return RandomPlayout(g, moves, rng)
+}
+
+// mctsNode is one position of the search tree, reached by playing move from its parent
+type mctsNode struct {
+	move     Move
+	mover    PieceColor // Player who played move; wins are counted for them
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []Move // Moves not expanded yet; nil until the node is first visited
+	visits   int
+	wins     float64 // Sum of playout results for mover: 1 a win, ½ a draw
+	key      uint64  // Zobrist key of the position, to check a reused tree still fits the game
+}
+
+// MCTSEngine searches with Monte Carlo Tree Search: it grows a tree towards the moves whose
+// random playouts win most often, balancing the two with the UCT formula
+// Unlike alpha-beta it needs no full-width search, which suits Hive's many ant moves
+type MCTSEngine struct {
+	Exploration  float64       // UCT exploration constant; higher tries more unlikely moves
+	Policy       PlayoutPolicy // How playouts choose moves
+	Iterations   int           // Playouts per move, 0 to be bounded by the time limit only
+	PlayoutDepth int           // Plies before a playout is cut short and scored by Weights
+	Weights      EvalWeights   // Scores playouts cut short
+
+	rng         *rand.Rand
+	root        *mctsNode
+	rootHistory []Move // Moves leading to root, to find it again next turn
+}
+
+// NewMCTSEngine creates an MCTS engine with the default settings
+func NewMCTSEngine() *MCTSEngine {
+	return &MCTSEngine{
+		Exploration:  DefaultExploration,
+		Policy:       QueenPressurePlayout,
+		PlayoutDepth: 40,
+		Weights:      DefaultEvalWeights,
+		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Name identifies the engine in menus and match results
+func (e *MCTSEngine) Name() string {
+	return "MCTS"
+}
+
+// BestMove grows the tree within the limits and plays the most visited move
+// The iteration budget and the time limit both apply, whichever runs out first; with
+// neither the engine runs 1000 iterations. Depth does not apply to MCTS and is ignored.
+// The subtree of the chosen move is kept, so the next search starts where this one left off
+func (e *MCTSEngine) BestMove(g *GameState, limits SearchLimits) Move {
+	state := g.Clone()
+	moves := state.LegalMoves()
+	if len(moves) == 0 {
+		return Move{Type: PassMove}
+	}
+	if len(moves) == 1 {
+		return moves[0]
+	}
+
+	iterations := e.Iterations
+	if iterations <= 0 && limits.Time <= 0 {
+		iterations = 1000
+	}
+	var deadline time.Time
+	if limits.Time > 0 {
+		deadline = time.Now().Add(limits.Time)
+	}
+
+	// The clock is only checked after the first iteration, so the root always has a child
+	// to play even when the time limit is already spent
+	root := e.reuseTree(state)
+	for i := 0; iterations <= 0 || i < iterations; i++ {
+		if i > 0 && !deadline.IsZero() && i%16 == 0 && time.Now().After(deadline) {
+			break
+		}
+		e.iterate(state, root)
+	}
+
+	best := root.children[0]
+	for _, child := range root.children[1:] {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+
+	best.parent = nil
+	e.root = best
+	e.rootHistory = append(append([]Move{}, state.History()...), best.move)
+	return best.move
+}
+
+// reuseTree finds the position to search in the tree kept from the last move, following
+// the moves played since; a game that went elsewhere starts a fresh tree
+func (e *MCTSEngine) reuseTree(g *GameState) *mctsNode {
+	fresh := &mctsNode{mover: g.ToMove.Opponent(), key: g.ZobristKey()}
+
+	history := g.History()
+	if e.root == nil || len(history) < len(e.rootHistory) {
+		return fresh
+	}
+	for i, move := range e.rootHistory {
+		if history[i] != move {
+			return fresh
+		}
+	}
+
+	node := e.root
+	for _, move := range history[len(e.rootHistory):] {
+		var next *mctsNode
+		for _, child := range node.children {
+			if child.move == move {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return fresh
+		}
+		node = next
+	}
+	if node.key != g.ZobristKey() {
+		return fresh
+	}
+	node.parent = nil
+	return node
+}
+
+// iterate runs one round of selection, expansion, playout and backpropagation
+// Moves are played on g and all taken back before returning
+func (e *MCTSEngine) iterate(g *GameState, root *mctsNode) {
+	played := 0
+	defer func() {
+		for ; played > 0; played-- {
+			g.undo()
+		}
+	}()
+
+	// Selection: walk down through fully expanded nodes
+	node := root
+	for !g.IsOver() {
+		if node.untried == nil && len(node.children) == 0 {
+			node.untried = g.LegalMoves()
+		}
+		if len(node.untried) > 0 {
+			break
+		}
+		node = e.selectChild(node)
+		g.apply(node.move)
+		played++
+	}
+
+	// Expansion: add one untried move to the tree
+	if !g.IsOver() {
+		i := e.rng.Intn(len(node.untried))
+		move := node.untried[i]
+		node.untried[i] = node.untried[len(node.untried)-1]
+		node.untried = node.untried[:len(node.untried)-1]
+
+		mover := g.ToMove
+		g.apply(move)
+		played++
+		child := &mctsNode{move: move, mover: mover, parent: node, key: g.ZobristKey()}
+		node.children = append(node.children, child)
+		node = child
+	}
+
+	// Playout: finish the game, or score it once it has gone on long enough
+	depth := 0
+	for ; !g.IsOver() && depth < e.PlayoutDepth; depth++ {
+		g.apply(e.Policy(g, g.LegalMoves(), e.rng))
+	}
+	white := e.playoutResult(g)
+	for ; depth > 0; depth-- {
+		g.undo()
+	}
+
+	// Backpropagation: credit every node on the path from the point of view of its mover
+	for ; node != nil; node = node.parent {
+		node.visits++
+		if node.mover == White {
+			node.wins += white
+		} else {
+			node.wins += 1 - white
+		}
+	}
+}
+
+// selectChild picks the child with the highest upper confidence bound
+func (e *MCTSEngine) selectChild(node *mctsNode) *mctsNode {
+	logVisits := math.Log(float64(node.visits))
+
+	var best *mctsNode
+	bestScore := math.Inf(-1)
+	for _, child := range node.children {
+		score := child.wins/float64(child.visits) + e.Exploration*math.Sqrt(logVisits/float64(child.visits))
+		if score > bestScore {
+			best, bestScore = child, score
+		}
+	}
+	return best
+}
+
+// playoutResult scores the end of a playout for White, between 0 and 1
+// An unfinished game turns its evaluation into a winning chance
+func (e *MCTSEngine) playoutResult(g *GameState) float64 {
+	switch g.Result {
+	case WhiteWins:
+		return 1
+	case BlackWins:
+		return 0
+	case Draw:
+		return 0.5
+	}
+
+	score := e.Weights.Evaluate(g)
+	if g.ToMove == Black {
+		score = -score
+	}
+	return 1 / (1 + math.Exp(-float64(score)/400))
+}
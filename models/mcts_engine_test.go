@@ -0,0 +1,52 @@
+package models
+
+import "testing"
+
+func TestMCTSBestMoveIsLegal(t *testing.T) {
+	games := map[string]*GameState{
+		"empty board":       NewGameState(BaseRules),
+		"beetle on a stack": playMoves(t, BaseRules, beetleOnStack...),
+	}
+	for name, g := range games {
+		engine := NewMCTSEngine()
+		engine.Iterations, engine.PlayoutDepth = 50, 8
+		move := engine.BestMove(g, SearchLimits{})
+		legal := false
+		for _, m := range g.LegalMoves() {
+			legal = legal || m == move
+		}
+		if !legal {
+			t.Errorf("%s: BestMove returned illegal move %s", name, move)
+		}
+	}
+}
+
+func TestMCTSReusesTree(t *testing.T) {
+	g := playMoves(t, BaseRules, beetleOnStack...)
+	engine := NewMCTSEngine()
+	engine.Iterations, engine.PlayoutDepth = 300, 8
+
+	move := engine.BestMove(g, SearchLimits{})
+	kept := engine.root
+	if len(kept.children) == 0 {
+		t.Fatalf("the subtree of %s was not expanded in %d iterations", move, engine.Iterations)
+	}
+	reply := kept.children[0]
+
+	if _, err := g.Play(move); err != nil {
+		t.Fatalf("playing %s: %v", move, err)
+	}
+	if _, err := g.Play(reply.move); err != nil {
+		t.Fatalf("playing %s: %v", reply.move, err)
+	}
+	if node := engine.reuseTree(g); node != reply {
+		t.Errorf("after %s %s the tree was not found again", move, reply.move)
+	} else if node.visits == 0 || node.parent != nil {
+		t.Errorf("the reused node has %d visits and parent %v", node.visits, node.parent)
+	}
+
+	// A game that went elsewhere starts afresh
+	if node := engine.reuseTree(NewGameState(BaseRules)); node.visits != 0 || len(node.children) != 0 {
+		t.Errorf("a different game reused a node with %d visits", node.visits)
+	}
+}
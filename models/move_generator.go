@@ -0,0 +1,196 @@
+package models
+
+// LegalMoves returns every legal placement and movement for the side to move
+// A player with nothing else to do gets a single pass; a finished game has no moves at all
+func (g *GameState) LegalMoves() []Move {
+	if g.IsOver() {
+		return nil
+	}
+
+	moves := g.generateMoves(false)
+	if len(moves) == 0 {
+		return []Move{{Type: PassMove}}
+	}
+	return moves
+}
+
+// HasLegalMove reports whether the side to move can place or move any piece
+// A player without one must pass
+func (g *GameState) HasLegalMove() bool {
+	return len(g.generateMoves(true)) > 0
+}
+
+// generateMoves lists placements then movements for the side to move
+// With firstOnly set it stops as soon as one move is found
+func (g *GameState) generateMoves(firstOnly bool) []Move {
+	color := g.ToMove
+	moves := []Move{}
+
+	// Placement legality only depends on the colour, so the cells are worked out once
+	cells := []HexCoordinate{}
+	for _, cell := range g.placementCandidates() {
+		if ValidatePlacement(g.Board, NewPiece(QueenBee, color, 0), cell) == nil {
+			cells = append(cells, cell)
+		}
+	}
+
+	if len(cells) > 0 {
+		for _, piece := range g.placeablePieces(color) {
+			for _, cell := range cells {
+				moves = append(moves, Move{Type: PlaceMove, Piece: piece, To: cell})
+				if firstOnly {
+					return moves
+				}
+			}
+		}
+	}
+
+	// Nothing may move before the Queen is on the board
+	if !g.QueenPlaced(color) {
+		return moves
+	}
+
+	// Generating a move lifts the piece off the board, so the cells are listed up front
+	// rather than ranging over the map while it changes
+	// The pinned pieces are found in one pass over the hive rather than once per piece
+	last, hasLast := g.lastMovedPiece()
+	pinned := g.Board.PinnedPieces()
+	for _, coord := range g.Board.GetAllCoordinates() {
+		piece, _ := g.Board.GetTopPiece(coord)
+		if piece.Color != color || (hasLast && piece == last) || pinned[coord] {
+			continue
+		}
+		for _, dest := range g.Board.liftedDestinations(coord) {
+			moves = append(moves, Move{Type: MovementMove, Piece: piece, From: coord, To: dest})
+			if firstOnly {
+				return moves
+			}
+		}
+	}
+
+	// Pillbug throws, skipping those that duplicate a piece's own movement
+	for _, throw := range g.throwMoves() {
+		if throw.Piece.Color == color && containsMove(moves, throw) {
+			continue
+		}
+		moves = append(moves, throw)
+		if firstOnly {
+			return moves
+		}
+	}
+
+	return moves
+}
+
+// throwMoves returns every move made with the special ability of the side to move's
+// pillbugs, or of mosquitoes touching a pillbug
+func (g *GameState) throwMoves() []Move {
+	if !g.Rules.Pillbug || !g.QueenPlaced(g.ToMove) {
+		return nil
+	}
+
+	last, hasLast := g.lastMovedPiece()
+	moves := []Move{}
+	for _, coord := range g.Board.GetAllCoordinates() {
+		thrower, _ := g.Board.GetTopPiece(coord)
+		if thrower.Color != g.ToMove || (hasLast && thrower == last) || !g.Board.CanThrow(coord) {
+			continue
+		}
+		for _, target := range coord.Neighbors() {
+			piece, occupied := g.Board.GetTopPiece(target)
+			if !occupied || (hasLast && piece == last) {
+				continue
+			}
+			for _, dest := range g.Board.PillbugThrows(coord, target) {
+				move := Move{Type: MovementMove, Piece: piece, From: target, To: dest}
+				if !containsMove(moves, move) {
+					moves = append(moves, move)
+				}
+			}
+		}
+	}
+	return moves
+}
+
+// containsMove checks whether moves includes the given move
+func containsMove(moves []Move, move Move) bool {
+	for _, m := range moves {
+		if m == move {
+			return true
+		}
+	}
+	return false
+}
+
+// placeablePieces returns the next piece of each type the player may place this turn
+func (g *GameState) placeablePieces(color PieceColor) []Piece {
+	pieces := []Piece{}
+	for _, info := range g.Rules.PieceTypes() {
+		piece, ok := g.NextPiece(color, info.Symbol)
+		if !ok {
+			continue
+		}
+		if g.checkQueenTiming(piece) != nil {
+			continue
+		}
+		pieces = append(pieces, piece)
+	}
+	return pieces
+}
+
+// placementCandidates returns the cells worth checking for a placement: the origin on an empty
+// board, otherwise every empty cell next to the hive
+func (g *GameState) placementCandidates() []HexCoordinate {
+	if len(g.Board.Pieces) == 0 {
+		return []HexCoordinate{NewHexCoordinate(0, 0)}
+	}
+
+	seen := make(map[HexCoordinate]bool)
+	cells := []HexCoordinate{}
+	for coord := range g.Board.Pieces {
+		for _, neighbor := range coord.Neighbors() {
+			if !seen[neighbor] && !g.Board.IsOccupied(neighbor) {
+				seen[neighbor] = true
+				cells = append(cells, neighbor)
+			}
+		}
+	}
+	return cells
+}
+
+// Perft counts the leaf nodes of the legal move tree to the given depth
+// Comparing the counts against known values catches regressions in the rules
+func (g *GameState) Perft(depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+
+	moves := g.LegalMoves()
+	if depth == 1 {
+		return uint64(len(moves))
+	}
+
+	var nodes uint64
+	for _, move := range moves {
+		g.apply(move)
+		nodes += g.Perft(depth - 1)
+		g.undo()
+	}
+	return nodes
+}
+
+// PerftDivide runs perft below each legal move separately, which helps find the
+// branch where two move generators disagree
+func (g *GameState) PerftDivide(depth int) map[string]uint64 {
+	counts := make(map[string]uint64)
+	if depth < 1 {
+		return counts
+	}
+
+	for _, move := range g.LegalMoves() {
+		g.apply(move)
+		counts[move.String()] = g.Perft(depth - 1)
+		g.undo()
+	}
+	return counts
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+// playMoves plays the moves on a new game, failing the test on the first one that is rejected
+func playMoves(t *testing.T, rules RuleSet, moves ...Move) *GameState {
+	t.Helper()
+	g := NewGameState(rules)
+	for _, move := range moves {
+		if _, err := g.Play(move); err != nil {
+			t.Fatalf("playing %s: %v", move, err)
+		}
+	}
+	return g
+}
+
+func place(pieceType PieceType, color PieceColor, number, q, r int) Move {
+	return Move{Type: PlaceMove, Piece: NewPiece(pieceType, color, number), To: NewHexCoordinate(q, r)}
+}
+
+func movement(pieceType PieceType, color PieceColor, number, fromQ, fromR, toQ, toR int) Move {
+	return Move{Type: MovementMove, Piece: NewPiece(pieceType, color, number),
+		From: NewHexCoordinate(fromQ, fromR), To: NewHexCoordinate(toQ, toR)}
+}
+
+// beetleOnStack leaves White's beetle on top of Black's Queen, Black to move
+var beetleOnStack = []Move{
+	place(QueenBee, White, 0, 0, 0),
+	place(QueenBee, Black, 0, 1, 0),
+	place(Beetle, White, 1, -1, 0),
+	place(Ant, Black, 1, 2, 0),
+	movement(Beetle, White, 1, -1, 0, 0, 0),
+	place(Grasshopper, Black, 1, 3, 0),
+	movement(Beetle, White, 1, 0, 0, 1, 0),
+}
+
+// tournamentRules is the base game with the Tournament Opening
+var tournamentRules = RuleSet{TournamentOpening: true}
+
+// perftCases are leaf counts the generator must reproduce, from the moves played or from a
+// position string. The Tournament Opening counts are the published base-game perft numbers.
+// The others were worked out here rather than taken from a published source: the shallower
+// ones by hand, the deeper ones from this generator once those agreed, so that rules changes
+// show up as regressions
+var perftCases = []struct {
+	name     string
+	rules    RuleSet
+	moves    []Move
+	position string
+	depth    int
+	nodes    uint64
+}{
+	{"empty board", BaseRules, nil, "", 1, 5},
+	{"empty board", BaseRules, nil, "", 2, 150},
+	{"empty board", BaseRules, nil, "", 3, 2220},
+	{"Tournament Opening", tournamentRules, nil, "", 1, 4},
+	{"Tournament Opening", tournamentRules, nil, "", 2, 96},
+	{"Tournament Opening", tournamentRules, nil, "", 3, 1440},
+	{"beetle on a stack", BaseRules, beetleOnStack, "", 1, 21},
+	{"beetle on a stack", BaseRules, beetleOnStack, "", 2, 574},
+	{"pillbug throws", BaseRules, nil, "Base+P - w 3 1,-1:wP;2,-1:bA1;0,0:wQ;1,0:bQ Q0A3G3S2B2P0/Q0A2G3S2B2P1 bA1", 1, 25},
+	{"pillbug throws", BaseRules, nil, "Base+P - w 3 1,-1:wP;2,-1:bA1;0,0:wQ;1,0:bQ Q0A3G3S2B2P0/Q0A2G3S2B2P1 bA1", 2, 790},
+	{"mosquito and ladybug", BaseRules, nil, "Base+MLP - w 4 2,-1:bL;-1,0:wM;0,0:wQ;1,0:bQ;2,0:bP;-1,1:wL Q0A3G3S2B2M0L0P1/Q0A3G3S2B2M1L0P0 bL", 1, 44},
+	{"mosquito and ladybug", BaseRules, nil, "Base+MLP - w 4 2,-1:bL;-1,0:wM;0,0:wQ;1,0:bQ;2,0:bP;-1,1:wL Q0A3G3S2B2M0L0P1/Q0A3G3S2B2M1L0P0 bL", 2, 1699},
+}
+
+func TestPerft(t *testing.T) {
+	for _, tc := range perftCases {
+		g := playMoves(t, tc.rules, tc.moves...)
+		if tc.position != "" {
+			var err error
+			if g, err = ParsePosition(tc.position); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+		}
+		before := fmt.Sprint(g.Board.Pieces)
+		if nodes := g.Perft(tc.depth); nodes != tc.nodes {
+			t.Errorf("%s: perft(%d) = %d, want %d", tc.name, tc.depth, nodes, tc.nodes)
+		}
+		if after := fmt.Sprint(g.Board.Pieces); after != before {
+			t.Errorf("%s: perft left the board as %s instead of %s", tc.name, after, before)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Standard Hive notation names a move by the moving piece and a reference piece next to
+// the destination, e.g. "wS1 bG1-" puts wS1 to the right of bG1. The marker before or after
+// the reference says in which direction: index i matches HexCoordinate.Neighbors()[i].
+var notationMarkers = []struct {
+	marker string
+	before bool // Marker written before the reference piece
+}{
+	{"-", false},  // East
+	{"/", false},  // Northeast
+	{"\\", true},  // Northwest
+	{"-", true},   // West
+	{"/", true},   // Southwest
+	{"\\", false}, // Southeast
+}
+
+// NotationPiece returns a piece in notation form, with a lowercase colour: "wQ", "bA1"
+func NotationPiece(piece Piece) string {
+	return strings.ToLower(string(piece.Color)) + piece.String()[1:]
+}
+
+// ParseNotationPiece reads a piece in notation form, in any case: "wA1", "WA1" or "wa1"
+func ParseNotationPiece(s string) (Piece, error) {
+	return ParsePieceString(strings.ToUpper(s))
+}
+
+// MoveString describes a move in standard notation, relative to the board before it is played
+func (g *GameState) MoveString(move Move) string {
+	if move.Type == PassMove {
+		return "pass"
+	}
+
+	piece := NotationPiece(move.Piece)
+	if g.Board.PieceCount() == 0 {
+		return piece
+	}
+
+	// Climbing onto a stack names the piece it lands on
+	if top, occupied := g.Board.GetTopPiece(move.To); occupied {
+		return fmt.Sprintf("%s %s", piece, NotationPiece(top))
+	}
+
+	for dir, neighbor := range move.To.Neighbors() {
+		ref, ok := g.referencePiece(neighbor, move)
+		if !ok {
+			continue
+		}
+		// The destination lies in the opposite direction when seen from the reference
+		marker := notationMarkers[(dir+3)%6]
+		if marker.before {
+			return fmt.Sprintf("%s %s%s", piece, marker.marker, NotationPiece(ref))
+		}
+		return fmt.Sprintf("%s %s%s", piece, NotationPiece(ref), marker.marker)
+	}
+
+	return piece
+}
+
+// referencePiece returns the piece a move can be described against at coord,
+// ignoring the moving piece itself
+func (g *GameState) referencePiece(coord HexCoordinate, move Move) (Piece, bool) {
+	stack := g.Board.Pieces[coord]
+	if move.Type == MovementMove && coord.Equals(move.From) {
+		stack = stack[:len(stack)-1]
+	}
+	if len(stack) == 0 {
+		return Piece{}, false
+	}
+	return stack[len(stack)-1], true
+}
+
+// ParseMoveString resolves a move in standard notation against the current board
+// The moving piece decides between a placement and a movement: pieces on the board move
+func (g *GameState) ParseMoveString(s string) (Move, error) {
+	parts := strings.Fields(s)
+	if len(parts) == 1 && strings.EqualFold(parts[0], "pass") {
+		return Move{Type: PassMove}, nil
+	}
+
+	command := parseNotationCommand(parts, g.Board)
+	if command.Type == InvalidCommand {
+		return Move{}, fmt.Errorf("%s", command.Error)
+	}
+
+	piece, err := ParsePieceString(command.Piece)
+	if err != nil {
+		return Move{}, err
+	}
+	if command.Type == MoveCommand {
+		return Move{Type: MovementMove, Piece: piece, From: command.FromCoord, To: command.ToCoord}, nil
+	}
+	return Move{Type: PlaceMove, Piece: piece, To: command.ToCoord}, nil
+}
+
+// ResolveReference turns a notation reference like "bG1-", "/wQ" or "wB2" into the
+// coordinate it names: next to the reference piece, or on top of it without a marker
+func (b *HexBoard) ResolveReference(ref string) (HexCoordinate, error) {
+	name := strings.Trim(ref, "-/\\")
+	if name == "" {
+		return HexCoordinate{}, fmt.Errorf("invalid reference: %s", ref)
+	}
+	prefix := ref[:strings.Index(ref, name)]
+	suffix := ref[len(prefix)+len(name):]
+	if len(prefix)+len(suffix) > 1 {
+		return HexCoordinate{}, fmt.Errorf("invalid reference: %s", ref)
+	}
+
+	piece, err := ParseNotationPiece(name)
+	if err != nil {
+		return HexCoordinate{}, err
+	}
+	coord, found := b.FindPiece(piece)
+	if !found {
+		return HexCoordinate{}, fmt.Errorf("reference piece %s not on board", name)
+	}
+
+	if prefix == "" && suffix == "" {
+		return coord, nil
+	}
+
+	for dir, marker := range notationMarkers {
+		if (marker.before && prefix == marker.marker) || (!marker.before && suffix == marker.marker) {
+			return coord.Neighbors()[dir], nil
+		}
+	}
+	return HexCoordinate{}, fmt.Errorf("invalid reference: %s", ref)
+}
+
+// MoveStrings returns the history in standard notation by replaying it from the start,
+// since every move is written relative to the board as it was at the time
+func (g *GameState) MoveStrings() []string {
+	replay := g.startState()
+	strs := make([]string, 0, len(g.history))
+	for _, move := range g.history {
+		strs = append(strs, replay.MoveString(move))
+		replay.apply(move)
+	}
+	return strs
+}
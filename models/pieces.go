@@ -9,6 +9,11 @@ const (
 	Grasshopper  PieceType = "G"
 	Spider       PieceType = "S"
 	Beetle       PieceType = "B"
+	
+	// Expansion pieces, only in play when enabled in the game's RuleSet
+	Mosquito     PieceType = "M"
+	Ladybug      PieceType = "L"
+	Pillbug      PieceType = "P"
 )
 
 // PieceColor represents which player owns the piece
@@ -19,6 +24,22 @@ const (
 	Black PieceColor = "B"
 )
 
+// Name returns the full name of the colour
+func (c PieceColor) Name() string {
+	if c == Black {
+		return "Black"
+	}
+	return "White"
+}
+
+// Opponent returns the other player's colour
+func (c PieceColor) Opponent() PieceColor {
+	if c == Black {
+		return White
+	}
+	return Black
+}
+
 // Piece represents a single game piece
 type Piece struct {
 	Type   PieceType
@@ -36,8 +57,9 @@ func NewPiece(pieceType PieceType, color PieceColor, number int) Piece {
 }
 
 // String returns a string representation of the piece (e.g., "WQ", "BA1", "WS2")
+// Pieces that come one per player, like the Queen or the Mosquito, have no number
 func (p Piece) String() string {
-	if p.Type == QueenBee {
+	if p.Number == 0 {
 		return string(p.Color) + string(p.Type)
 	}
 	return string(p.Color) + string(p.Type) + string(rune('0'+p.Number))
@@ -50,18 +72,33 @@ func (p Piece) ShortString() string {
 
 // PieceInfo holds metadata about each piece type
 type PieceInfo struct {
-	Name     string
-	Symbol   PieceType
-	Quantity int
+	Name      string
+	Symbol    PieceType
+	Quantity  int
+	Movement  string // Short description of how the piece moves
+	Expansion bool   // Only used when the expansion is enabled for the game
 }
 
-// GetAllPieceTypes returns information about all piece types
+// GetAllPieceTypes returns information about all piece types, base game first
 func GetAllPieceTypes() []PieceInfo {
 	return []PieceInfo{
-		{Name: "Queen Bee", Symbol: QueenBee, Quantity: 1},
-		{Name: "Ant", Symbol: Ant, Quantity: 3},
-		{Name: "Grasshopper", Symbol: Grasshopper, Quantity: 3},
-		{Name: "Spider", Symbol: Spider, Quantity: 2},
-		{Name: "Beetle", Symbol: Beetle, Quantity: 2},
+		{Name: "Queen Bee", Symbol: QueenBee, Quantity: 1, Movement: "slides exactly one cell"},
+		{Name: "Ant", Symbol: Ant, Quantity: 3, Movement: "slides any distance around the hive"},
+		{Name: "Grasshopper", Symbol: Grasshopper, Quantity: 3, Movement: "jumps in a straight line over at least one piece"},
+		{Name: "Spider", Symbol: Spider, Quantity: 2, Movement: "slides exactly three distinct cells"},
+		{Name: "Beetle", Symbol: Beetle, Quantity: 2, Movement: "moves one cell and may climb onto the hive"},
+		{Name: "Mosquito", Symbol: Mosquito, Quantity: 1, Movement: "moves like any insect it touches", Expansion: true},
+		{Name: "Ladybug", Symbol: Ladybug, Quantity: 1, Movement: "moves two cells on top of the hive, then one down", Expansion: true},
+		{Name: "Pillbug", Symbol: Pillbug, Quantity: 1, Movement: "slides one cell or moves an adjacent piece over itself", Expansion: true},
+	}
+}
+
+// GetPieceInfo returns the metadata for a single piece type
+func GetPieceInfo(pieceType PieceType) (PieceInfo, bool) {
+	for _, info := range GetAllPieceTypes() {
+		if info.Symbol == pieceType {
+			return info, true
+		}
 	}
+	return PieceInfo{}, false
 }
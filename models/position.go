@@ -0,0 +1,257 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A position string captures a Hive position without its history, for test fixtures
+// and bug reports. It has seven space separated fields:
+//
+//	Base+M T w 5 0,0:wQ;1,0:bQ.wB1;-1,0:wA1 Q0A2G3S2B1M1/Q0A3G3S2B2M1 wB1
+//
+//  1. game type, as in RuleSet.String
+//  2. T when the Tournament Opening is in force, - otherwise
+//  3. side to move, w or b
+//  4. turn number
+//  5. the board, see HexBoard.Encode
+//  6. White's then Black's reserve as piece type and count
+//  7. the piece moved on the previous turn, which a pillbug may not touch, or -
+
+// Encode describes every stack on the board as q,r:bottom.….top, separated by semicolons
+// Stacks are ordered by row then column, so equal boards always encode the same way
+func (b *HexBoard) Encode() string {
+	return encodeStacks(b.Pieces)
+}
+
+// encodeStacks writes the stacks of a board, or of a transformed copy of its layout
+func encodeStacks(stacks map[HexCoordinate][]Piece) string {
+	if len(stacks) == 0 {
+		return "-"
+	}
+
+	coords := make([]HexCoordinate, 0, len(stacks))
+	for coord := range stacks {
+		coords = append(coords, coord)
+	}
+	sort.Slice(coords, func(i, j int) bool {
+		if coords[i].R != coords[j].R {
+			return coords[i].R < coords[j].R
+		}
+		return coords[i].Q < coords[j].Q
+	})
+
+	parts := make([]string, 0, len(coords))
+	for _, coord := range coords {
+		pieces := make([]string, 0, len(stacks[coord]))
+		for _, piece := range stacks[coord] {
+			pieces = append(pieces, NotationPiece(piece))
+		}
+		parts = append(parts, fmt.Sprintf("%d,%d:%s", coord.Q, coord.R, strings.Join(pieces, ".")))
+	}
+	return strings.Join(parts, ";")
+}
+
+// DecodeHexBoard rebuilds a board from the output of Encode
+func DecodeHexBoard(s string) (*HexBoard, error) {
+	board := NewHexBoard()
+	if s == "-" {
+		return board, nil
+	}
+
+	seen := make(map[Piece]bool)
+	for _, stack := range strings.Split(s, ";") {
+		coordText, piecesText, found := strings.Cut(stack, ":")
+		qText, rText, isPair := strings.Cut(coordText, ",")
+		if !found || !isPair || piecesText == "" {
+			return nil, fmt.Errorf("invalid stack %q, expected q,r:pieces", stack)
+		}
+		q, errQ := strconv.Atoi(qText)
+		r, errR := strconv.Atoi(rText)
+		if errQ != nil || errR != nil {
+			return nil, fmt.Errorf("invalid coordinate %q", coordText)
+		}
+
+		coord := NewHexCoordinate(q, r)
+		if board.IsOccupied(coord) {
+			return nil, fmt.Errorf("cell %s appears twice", coordText)
+		}
+		for _, name := range strings.Split(piecesText, ".") {
+			piece, err := ParseNotationPiece(name)
+			if err != nil {
+				return nil, err
+			}
+			if seen[piece] {
+				return nil, fmt.Errorf("piece %s appears twice", name)
+			}
+			seen[piece] = true
+			board.PlacePiece(coord, piece)
+		}
+	}
+	return board, nil
+}
+
+// PositionString describes the current position, see ParsePosition for the reverse
+func (g *GameState) PositionString() string {
+	tournament := "-"
+	if g.Rules.TournamentOpening {
+		tournament = "T"
+	}
+
+	last := "-"
+	if piece, ok := g.lastMovedPiece(); ok {
+		last = NotationPiece(piece)
+	}
+
+	return strings.Join([]string{
+		g.Rules.String(),
+		tournament,
+		strings.ToLower(string(g.ToMove)),
+		strconv.Itoa(g.Turn()),
+		g.Board.Encode(),
+		g.encodeReserve(White) + "/" + g.encodeReserve(Black),
+		last,
+	}, " ")
+}
+
+// encodeReserve lists how many pieces of each type a player holds, e.g. Q0A2G3S2B1
+func (g *GameState) encodeReserve(color PieceColor) string {
+	var sb strings.Builder
+	for _, info := range g.Rules.PieceTypes() {
+		sb.WriteString(fmt.Sprintf("%s%d", info.Symbol, g.reserves[color][info.Symbol]))
+	}
+	return sb.String()
+}
+
+// ParsePosition builds a game from a position string
+// The game starts at that position: there is no history to undo past it
+func ParsePosition(s string) (*GameState, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 7 {
+		return nil, fmt.Errorf("a position has 7 fields, found %d", len(fields))
+	}
+
+	rules, err := ParseRuleSet(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	switch fields[1] {
+	case "T":
+		rules.TournamentOpening = true
+	case "-":
+	default:
+		return nil, fmt.Errorf("invalid tournament flag %q, expected T or -", fields[1])
+	}
+
+	g := NewGameState(rules)
+	switch fields[2] {
+	case "w":
+		g.ToMove = White
+	case "b":
+		g.ToMove = Black
+	default:
+		return nil, fmt.Errorf("invalid side to move %q, expected w or b", fields[2])
+	}
+
+	turn, err := strconv.Atoi(fields[3])
+	if err != nil || turn < 1 {
+		return nil, fmt.Errorf("invalid turn number %q", fields[3])
+	}
+	g.Ply = 2 * (turn - 1)
+	if g.ToMove == Black {
+		g.Ply++
+	}
+
+	board, err := DecodeHexBoard(fields[4])
+	if err != nil {
+		return nil, err
+	}
+	g.Board = board
+
+	white, black, found := strings.Cut(fields[5], "/")
+	if !found {
+		return nil, fmt.Errorf("invalid reserves %q, expected white/black", fields[5])
+	}
+	if err := g.decodeReserve(White, white); err != nil {
+		return nil, err
+	}
+	if err := g.decodeReserve(Black, black); err != nil {
+		return nil, err
+	}
+	if err := g.checkPieceCounts(); err != nil {
+		return nil, err
+	}
+
+	if fields[6] != "-" {
+		piece, err := ParseNotationPiece(fields[6])
+		if err != nil {
+			return nil, err
+		}
+		if _, onBoard := board.FindPiece(piece); !onBoard {
+			return nil, fmt.Errorf("last moved piece %s is not on the board", fields[6])
+		}
+		g.startLastMoved, g.hasStartLastMoved = piece, true
+	}
+
+	g.startPosition = s
+	g.positions = map[uint64]int{g.positionKey(): 1}
+	g.updateResult()
+	return g, nil
+}
+
+// decodeReserve reads one player's reserve as written by encodeReserve
+func (g *GameState) decodeReserve(color PieceColor, s string) error {
+	counts := make(map[PieceType]int)
+	for i := 0; i+1 < len(s); i += 2 {
+		counts[PieceType(s[i])] = int(s[i+1] - '0')
+	}
+
+	expected := g.encodeReserve(color)
+	if len(s) != len(expected) {
+		return fmt.Errorf("invalid %s reserve %q, expected the form %s", color.Name(), s, expected)
+	}
+	for _, info := range g.Rules.PieceTypes() {
+		count, ok := counts[info.Symbol]
+		if !ok || count < 0 || count > info.Quantity {
+			return fmt.Errorf("invalid %s reserve %q for %s", color.Name(), s, info.Name)
+		}
+		g.reserves[color][info.Symbol] = count
+	}
+	return nil
+}
+
+// checkPieceCounts makes sure the board and reserves together hold exactly each player's set
+func (g *GameState) checkPieceCounts() error {
+	onBoard := make(map[PieceColor]map[PieceType]int)
+	for _, color := range []PieceColor{White, Black} {
+		onBoard[color] = make(map[PieceType]int)
+	}
+	for _, stack := range g.Board.Pieces {
+		for _, piece := range stack {
+			if !g.Rules.Allows(piece.Type) {
+				return fmt.Errorf("piece %s is not part of %s", NotationPiece(piece), g.Rules)
+			}
+			onBoard[piece.Color][piece.Type]++
+		}
+	}
+
+	for _, color := range []PieceColor{White, Black} {
+		for _, info := range g.Rules.PieceTypes() {
+			placed := onBoard[color][info.Symbol]
+			if placed+g.reserves[color][info.Symbol] != info.Quantity {
+				return fmt.Errorf("%s has %d %s on the board and %d in reserve, expected %d in all",
+					color.Name(), placed, info.Name, g.reserves[color][info.Symbol], info.Quantity)
+			}
+			// Pieces come out of the reserve in order, so the placed ones are numbered 1..placed
+			for n := 1; n <= placed; n++ {
+				piece := g.numberedPiece(info, color, n)
+				if _, found := g.Board.FindPiece(piece); !found {
+					return fmt.Errorf("%s is missing from the board, pieces are placed in number order", NotationPiece(piece))
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// roundTripPositions are position strings in the form PositionString writes them
+var roundTripPositions = []string{
+	"Base - w 1 - Q1A3G3S2B2/Q1A3G3S2B2 -",
+	"Base T b 1 0,0:wG1 Q1A3G2S2B2/Q1A3G3S2B2 wG1",
+	"Base+M T b 5 -1,0:wA1;0,0:wQ;1,0:bQ.wB1.bM;2,0:bB1.bB2 Q0A2G3S2B1M1/Q0A3G3S2B0M0 wB1",
+	"Base+P - w 3 1,-1:wP;2,-1:bA1;0,0:wQ;1,0:bQ Q0A3G3S2B2P0/Q0A2G3S2B2P1 bA1",
+	"Base+MLP - w 4 2,-1:bL;-1,0:wM;0,0:wQ;1,0:bQ;2,0:bP;-1,1:wL Q0A3G3S2B2M0L0P1/Q0A3G3S2B2M1L0P0 bL",
+}
+
+func TestPositionStringRoundTrip(t *testing.T) {
+	for _, s := range roundTripPositions {
+		g, err := ParsePosition(s)
+		if err != nil {
+			t.Errorf("ParsePosition(%q): %v", s, err)
+			continue
+		}
+		if got := g.PositionString(); got != s {
+			t.Errorf("ParsePosition(%q).PositionString() = %q", s, got)
+		}
+	}
+}
+
+func TestPositionStringOfPlayedGame(t *testing.T) {
+	g := playMoves(t, BaseRules, beetleOnStack...)
+	s := g.PositionString()
+	parsed, err := ParsePosition(s)
+	if err != nil {
+		t.Fatalf("ParsePosition(%q): %v", s, err)
+	}
+	if got := parsed.PositionString(); got != s {
+		t.Errorf("position %q reads back as %q", s, got)
+	}
+	if !reflect.DeepEqual(parsed.Board.Pieces, g.Board.Pieces) {
+		t.Errorf("position %q reads back as board %v, want %v", s, parsed.Board.Pieces, g.Board.Pieces)
+	}
+	if parsed.ToMove != g.ToMove || parsed.Turn() != g.Turn() {
+		t.Errorf("position %q reads back with %s to move on turn %d, want %s on turn %d",
+			s, parsed.ToMove, parsed.Turn(), g.ToMove, g.Turn())
+	}
+}
+
+func TestHexBoardEncodeRoundTrip(t *testing.T) {
+	board := NewHexBoard()
+	board.PlacePiece(NewHexCoordinate(0, 0), NewPiece(QueenBee, White, 0))
+	board.PlacePiece(NewHexCoordinate(1, 0), NewPiece(QueenBee, Black, 0))
+	board.PlacePiece(NewHexCoordinate(1, 0), NewPiece(Beetle, White, 1))
+	board.PlacePiece(NewHexCoordinate(1, 0), NewPiece(Mosquito, Black, 0))
+	board.PlacePiece(NewHexCoordinate(-1, 1), NewPiece(Ladybug, White, 0))
+	board.PlacePiece(NewHexCoordinate(2, -1), NewPiece(Pillbug, Black, 0))
+	board.PlacePiece(NewHexCoordinate(-1, 0), NewPiece(Ant, White, 3))
+
+	encoded := board.Encode()
+	if want := "2,-1:bP;-1,0:wA3;0,0:wQ;1,0:bQ.wB1.bM;-1,1:wL"; encoded != want {
+		t.Errorf("Encode() = %q, want %q", encoded, want)
+	}
+	decoded, err := DecodeHexBoard(encoded)
+	if err != nil {
+		t.Fatalf("DecodeHexBoard(%q): %v", encoded, err)
+	}
+	if !reflect.DeepEqual(decoded.Pieces, board.Pieces) {
+		t.Errorf("DecodeHexBoard(%q) = %v, want %v", encoded, decoded.Pieces, board.Pieces)
+	}
+
+	empty, err := DecodeHexBoard(NewHexBoard().Encode())
+	if err != nil || len(empty.Pieces) != 0 {
+		t.Errorf("empty board reads back as %v, %v", empty.Pieces, err)
+	}
+}
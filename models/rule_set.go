@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleSet describes which variant of Hive a game is played with
+type RuleSet struct {
+	Mosquito bool
+	Ladybug  bool
+	Pillbug  bool
+
+	// TournamentOpening forbids placing the Queen on either player's first turn
+	TournamentOpening bool
+}
+
+// BaseRules is the base game without any expansion pieces
+var BaseRules = RuleSet{}
+
+// Allows reports whether pieces of the given type take part in the game
+func (r RuleSet) Allows(pieceType PieceType) bool {
+	switch pieceType {
+	case Mosquito:
+		return r.Mosquito
+	case Ladybug:
+		return r.Ladybug
+	case Pillbug:
+		return r.Pillbug
+	}
+	_, known := GetPieceInfo(pieceType)
+	return known
+}
+
+// PieceTypes returns information about every piece type in the game
+func (r RuleSet) PieceTypes() []PieceInfo {
+	types := []PieceInfo{}
+	for _, info := range GetAllPieceTypes() {
+		if r.Allows(info.Symbol) {
+			types = append(types, info)
+		}
+	}
+	return types
+}
+
+// String returns the variant name, e.g. "Base" or "Base+MLP"
+func (r RuleSet) String() string {
+	expansions := ""
+	for _, info := range r.PieceTypes() {
+		if info.Expansion {
+			expansions += string(info.Symbol)
+		}
+	}
+	if expansions == "" {
+		return "Base"
+	}
+	return "Base+" + expansions
+}
+
+// ParseRuleSet reads a game type as written by RuleSet.String, e.g. "Base+MLP"
+// The Tournament Opening is not part of the game type and is left switched off
+func ParseRuleSet(name string) (RuleSet, error) {
+	base, expansions, _ := strings.Cut(strings.TrimSpace(name), "+")
+	if !strings.EqualFold(base, "Base") {
+		return RuleSet{}, fmt.Errorf("unknown game type: %s", name)
+	}
+
+	rules := BaseRules
+	for _, symbol := range strings.ToUpper(expansions) {
+		switch PieceType(symbol) {
+		case Mosquito:
+			rules.Mosquito = true
+		case Ladybug:
+			rules.Ladybug = true
+		case Pillbug:
+			rules.Pillbug = true
+		default:
+			return RuleSet{}, fmt.Errorf("unknown expansion piece %q in %s", symbol, name)
+		}
+	}
+	return rules, nil
+}
+
+// Summary describes the whole rule set for display, e.g. "Base+M, Tournament Opening"
+func (r RuleSet) Summary() string {
+	if r.TournamentOpening {
+		return r.String() + ", Tournament Opening"
+	}
+	return r.String()
+}
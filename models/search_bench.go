@@ -0,0 +1,48 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// BenchmarkPosition is the middle game position the search benchmark starts from: both
+// Queens out, beetles and ants still in hand, so there is plenty to search
+const BenchmarkPosition = "Base - w 7 -2,0:wQ;-1,0:wG1;0,0:wS1;1,0:bS1;2,0:bS2;3,0:bQ;4,0:wS2;0,1:bG1;1,1:bB1 Q0A3G2S0B2/Q0A3G2S0B1 bG1"
+
+// SearchBenchmark is how fast the alpha-beta engine searched with a given number of workers
+type SearchBenchmark struct {
+	Workers int
+	Nodes   int64
+	Elapsed time.Duration
+}
+
+// NodesPerSecond is the search speed over all workers
+func (b SearchBenchmark) NodesPerSecond() float64 {
+	return float64(b.Nodes) / b.Elapsed.Seconds()
+}
+
+// MeasureSearchSpeed searches BenchmarkPosition for the given time with each worker count in
+// turn, writing the speed of each run and its speedup over the first to out
+func MeasureSearchSpeed(out io.Writer, workerCounts []int, budget time.Duration) ([]SearchBenchmark, error) {
+	g, err := ParsePosition(BenchmarkPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []SearchBenchmark{}
+	for _, workers := range workerCounts {
+		engine := NewAlphaBetaEngine()
+		engine.Workers = workers
+
+		start := time.Now()
+		engine.BestMove(g, SearchLimits{Time: budget})
+		result := SearchBenchmark{Workers: workers, Nodes: engine.Nodes(), Elapsed: time.Since(start)}
+		results = append(results, result)
+
+		fmt.Fprintf(out, "workers %2d: %9d nodes in %s, %9.0f nodes/s, %.2fx\n",
+			workers, result.Nodes, result.Elapsed.Round(time.Millisecond), result.NodesPerSecond(),
+			result.NodesPerSecond()/results[0].NodesPerSecond())
+	}
+	return results, nil
+}
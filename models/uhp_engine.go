@@ -0,0 +1,358 @@
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UHPEngineID is how the engine introduces itself to Universal Hive Protocol hosts
+const UHPEngineID = "id TerminalGames Hive v1.0"
+
+// maxUHPWorkers caps the Workers option, the number of goroutines bestmove searches with
+const maxUHPWorkers = 256
+
+// UHPEngine speaks the Universal Hive Protocol (UHP) so that Hive GUIs and engine
+// tournaments can drive the same rules engine as the terminal interface
+type UHPEngine struct {
+	state             *GameState
+	out               io.Writer
+	tournamentOpening bool
+	moveLimit         int
+	workers           int    // Goroutines bestmove searches with, for engines that search in parallel
+	engine            Engine // Answers bestmove
+}
+
+// NewUHPEngine creates an engine that writes its responses to out
+func NewUHPEngine(out io.Writer) *UHPEngine {
+	return &UHPEngine{out: out, workers: runtime.NumCPU(), engine: NewAlphaBetaEngine()}
+}
+
+// RunUHP runs a UHP session over the given streams until the input ends or "exit" is received
+func RunUHP(in io.Reader, out io.Writer) error {
+	engine := NewUHPEngine(out)
+	engine.Execute("info")
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "exit" {
+			return nil
+		}
+		engine.Execute(line)
+	}
+	return scanner.Err()
+}
+
+// Execute runs a single command and writes its response, always terminated by "ok"
+func (e *UHPEngine) Execute(line string) {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return
+	}
+
+	args := parts[1:]
+	switch strings.ToLower(parts[0]) {
+	case "info":
+		e.writeLines(UHPEngineID, "Mosquito;Ladybug;Pillbug")
+	case "newgame":
+		e.newGame(strings.TrimSpace(strings.TrimPrefix(line, parts[0])))
+	case "play":
+		e.play(strings.Join(args, " "))
+	case "pass":
+		e.play("pass")
+	case "validmoves":
+		e.validMoves()
+	case "bestmove":
+		e.bestMove(args)
+	case "undo":
+		e.undo(args)
+	case "options":
+		e.options(args)
+	default:
+		e.writeError(fmt.Sprintf("Invalid command. Try 'info' or 'newgame'. (%s)", parts[0]))
+	}
+
+	fmt.Fprintln(e.out, "ok")
+}
+
+// newGame starts a game from nothing, a game type like "Base+MLP", or a full GameString
+func (e *UHPEngine) newGame(arg string) {
+	fields := strings.Split(arg, ";")
+
+	rules := BaseRules
+	if arg != "" {
+		parsed, err := ParseRuleSet(fields[0])
+		if err != nil {
+			e.writeError(err.Error())
+			return
+		}
+		rules = parsed
+	}
+	rules.TournamentOpening = e.tournamentOpening
+
+	state := NewGameState(rules)
+	state.MoveLimit = e.moveLimit
+
+	// A GameString carries the moves after its state and turn fields
+	if len(fields) > 3 {
+		for _, moveString := range fields[3:] {
+			move, err := state.ParseMoveString(moveString)
+			if err == nil {
+				_, err = state.Play(move)
+			}
+			if err != nil {
+				e.writeError(fmt.Sprintf("cannot replay %q: %s", moveString, err))
+				return
+			}
+		}
+	}
+
+	e.state = state
+	e.writeLines(e.gameString())
+}
+
+// play applies a move given in standard notation
+func (e *UHPEngine) play(moveString string) {
+	if e.state == nil {
+		e.writeError("No game in progress. Try 'newgame' to start a new game.")
+		return
+	}
+
+	move, err := e.state.ParseMoveString(moveString)
+	if err == nil {
+		_, err = e.state.Play(move)
+	}
+	if err != nil {
+		e.writeLines(fmt.Sprintf("invalidmove %s", err))
+		return
+	}
+
+	e.writeLines(e.gameString())
+}
+
+// validMoves lists every legal move in standard notation, separated by semicolons
+func (e *UHPEngine) validMoves() {
+	if e.state == nil {
+		e.writeError("No game in progress. Try 'newgame' to start a new game.")
+		return
+	}
+
+	moves := []string{}
+	for _, move := range e.state.LegalMoves() {
+		moves = append(moves, e.state.MoveString(move))
+	}
+	e.writeLines(strings.Join(moves, ";"))
+}
+
+// bestMove suggests a move for the side to move
+func (e *UHPEngine) bestMove(args []string) {
+	if e.state == nil {
+		e.writeError("No game in progress. Try 'newgame' to start a new game.")
+		return
+	}
+	if e.state.IsOver() {
+		e.writeError("The game is over.")
+		return
+	}
+
+	limits, err := parseUHPLimits(args)
+	if err != nil {
+		e.writeError(err.Error())
+		return
+	}
+	if engine, ok := e.engine.(*AlphaBetaEngine); ok {
+		engine.Workers = e.workers
+	}
+	e.writeLines(e.state.MoveString(e.engine.BestMove(e.state, limits)))
+}
+
+// parseUHPLimits reads the limits of bestmove: "depth 4" or "time 00:00:05"
+// Without either the engine thinks for DefaultSearchLimits
+func parseUHPLimits(args []string) (SearchLimits, error) {
+	if len(args) == 0 {
+		return DefaultSearchLimits, nil
+	}
+	if len(args) != 2 {
+		return SearchLimits{}, fmt.Errorf("Invalid bestmove limits: %s", strings.Join(args, " "))
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "depth":
+		depth, err := strconv.Atoi(args[1])
+		if err != nil || depth < 1 {
+			return SearchLimits{}, fmt.Errorf("Invalid search depth: %s", args[1])
+		}
+		return SearchLimits{Depth: depth}, nil
+	case "time":
+		parts := strings.Split(args[1], ":")
+		if len(parts) != 3 {
+			return SearchLimits{}, fmt.Errorf("Invalid search time, expected hh:mm:ss: %s", args[1])
+		}
+		seconds := 0
+		for _, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return SearchLimits{}, fmt.Errorf("Invalid search time, expected hh:mm:ss: %s", args[1])
+			}
+			seconds = seconds*60 + n
+		}
+		if seconds == 0 {
+			return SearchLimits{}, fmt.Errorf("Invalid search time: %s", args[1])
+		}
+		return SearchLimits{Time: time.Duration(seconds) * time.Second}, nil
+	}
+	return SearchLimits{}, fmt.Errorf("Invalid bestmove limits: %s", strings.Join(args, " "))
+}
+
+// undo takes back one move, or as many as given
+func (e *UHPEngine) undo(args []string) {
+	if e.state == nil {
+		e.writeError("No game in progress. Try 'newgame' to start a new game.")
+		return
+	}
+
+	count := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			e.writeError(fmt.Sprintf("Invalid number of moves to undo: %s", args[0]))
+			return
+		}
+		count = n
+	}
+	if count > len(e.state.History()) {
+		e.writeError(fmt.Sprintf("Cannot undo %d moves, only %d played.", count, len(e.state.History())))
+		return
+	}
+
+	for i := 0; i < count; i++ {
+		e.state.Undo()
+	}
+	e.writeLines(e.gameString())
+}
+
+// options lists, reads or changes the engine options
+// Rule options apply from the next newgame on
+func (e *UHPEngine) options(args []string) {
+	if len(args) == 0 {
+		e.writeLines(e.optionLine("TournamentOpening"), e.optionLine("MoveLimit"), e.optionLine("Workers"))
+		return
+	}
+
+	if len(args) < 2 || (args[0] != "get" && args[0] != "set") {
+		e.writeError("Options format: options [get <name> | set <name> <value>]")
+		return
+	}
+
+	name := args[1]
+	if args[0] == "set" {
+		if len(args) < 3 {
+			e.writeError(fmt.Sprintf("Missing value for option %s.", name))
+			return
+		}
+		if err := e.setOption(name, args[2]); err != nil {
+			e.writeError(err.Error())
+			return
+		}
+	}
+
+	line := e.optionLine(name)
+	if line == "" {
+		e.writeError(fmt.Sprintf("Unknown option: %s", name))
+		return
+	}
+	e.writeLines(line)
+}
+
+// optionLine formats an option as Name;Type;Value;Default[;Min;Max]
+func (e *UHPEngine) optionLine(name string) string {
+	switch name {
+	case "TournamentOpening":
+		return fmt.Sprintf("TournamentOpening;bool;%s;False", uhpBool(e.tournamentOpening))
+	case "MoveLimit":
+		return fmt.Sprintf("MoveLimit;int;%d;0;0;1000", e.moveLimit)
+	case "Workers":
+		return fmt.Sprintf("Workers;int;%d;%d;1;%d", e.workers, runtime.NumCPU(), maxUHPWorkers)
+	}
+	return ""
+}
+
+// setOption changes an option from its UHP text value
+func (e *UHPEngine) setOption(name, value string) error {
+	switch name {
+	case "TournamentOpening":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid bool value for %s: %s", name, value)
+		}
+		e.tournamentOpening = enabled
+	case "MoveLimit":
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 || limit > 1000 {
+			return fmt.Errorf("Invalid int value for %s: %s", name, value)
+		}
+		e.moveLimit = limit
+	case "Workers":
+		workers, err := strconv.Atoi(value)
+		if err != nil || workers < 1 || workers > maxUHPWorkers {
+			return fmt.Errorf("Invalid int value for %s: %s", name, value)
+		}
+		e.workers = workers
+	default:
+		return fmt.Errorf("Unknown option: %s", name)
+	}
+	return nil
+}
+
+// gameString describes the current game as GameType;GameState;Turn;Move1;Move2;...
+func (e *UHPEngine) gameString() string {
+	fields := []string{
+		e.state.Rules.String(),
+		uhpGameState(e.state),
+		fmt.Sprintf("%s[%d]", e.state.ToMove.Name(), e.state.Turn()),
+	}
+	fields = append(fields, e.state.MoveStrings()...)
+	return strings.Join(fields, ";")
+}
+
+// uhpGameState names the state of a game the way UHP expects
+func uhpGameState(g *GameState) string {
+	switch g.Result {
+	case WhiteWins:
+		return "WhiteWins"
+	case BlackWins:
+		return "BlackWins"
+	case Draw:
+		return "Draw"
+	}
+	if len(g.History()) == 0 {
+		return "NotStarted"
+	}
+	return "InProgress"
+}
+
+// uhpBool formats a boolean as UHP's True or False
+func uhpBool(value bool) string {
+	if value {
+		return "True"
+	}
+	return "False"
+}
+
+func (e *UHPEngine) writeLines(lines ...string) {
+	for _, line := range lines {
+		fmt.Fprintln(e.out, line)
+	}
+}
+
+func (e *UHPEngine) writeError(message string) {
+	fmt.Fprintf(e.out, "err %s\n", message)
+}
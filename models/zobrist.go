@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"os"
+)
+
+// ZobristDebug makes every board recompute its Zobrist key from scratch after each change
+// and panic if the incremental key has drifted. It is slow, so it is off unless the
+// HIVE_ZOBRIST_DEBUG environment variable is set.
+var ZobristDebug = os.Getenv("HIVE_ZOBRIST_DEBUG") != ""
+
+// zobristBlackToMove is mixed into a game's key when Black is to move
+const zobristBlackToMove uint64 = 0x9e3779b97f4a7c15
+
+// zobristPiece returns the key of a piece standing at a cell at the given stack height
+// The board is unbounded, so instead of a table of random numbers the key is derived by
+// mixing the piece, cell and height together; it is just as stable and well spread
+func zobristPiece(piece Piece, coord HexCoordinate, height int) uint64 {
+	var identity uint64
+	if len(piece.Type) > 0 && len(piece.Color) > 0 {
+		identity = uint64(piece.Type[0])<<16 | uint64(piece.Color[0])<<8 | uint64(uint8(piece.Number))
+	}
+	return mix64(identity<<40 ^ uint64(uint16(coord.Q))<<24 ^ uint64(uint16(coord.R))<<8 ^ uint64(uint8(height)))
+}
+
+// mix64 is the splitmix64 finaliser: it spreads the bits of x evenly over the result
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// ZobristKey returns the board's hash, kept up to date by PlacePiece and RemovePiece
+// Unlike CanonicalHash it depends on where the pieces are, but costs nothing to read
+func (b *HexBoard) ZobristKey() uint64 {
+	return b.zobrist
+}
+
+// computeZobrist works the key out from every stack on the board
+func (b *HexBoard) computeZobrist() uint64 {
+	var key uint64
+	for coord, stack := range b.Pieces {
+		for i, piece := range stack {
+			key ^= zobristPiece(piece, coord, i+1)
+		}
+	}
+	return key
+}
+
+// checkZobrist panics when the incremental key no longer matches the board
+func (b *HexBoard) checkZobrist() {
+	if expected := b.computeZobrist(); b.zobrist != expected {
+		panic(fmt.Sprintf("zobrist key drifted: have %016x, board gives %016x", b.zobrist, expected))
+	}
+}
+
+// ZobristKey returns the hash of the position for the search: the board plus the side to move
+func (g *GameState) ZobristKey() uint64 {
+	if g.ToMove == Black {
+		return g.Board.ZobristKey() ^ zobristBlackToMove
+	}
+	return g.Board.ZobristKey()
+}